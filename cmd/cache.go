@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/longkey1/slago/internal/cache"
+	"github.com/longkey1/slago/internal/config"
+	"github.com/longkey1/slago/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var cacheClearAll bool
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the channel and user directory cache",
+		Long: `Manage the on-disk cache of channel and user names.
+
+The cache is stored per workspace under $XDG_CACHE_HOME/slago/<team-id>/
+and entries are refetched once they are older than --cache-ttl.`,
+	}
+
+	refreshCmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch all channels and users into the cache",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheRefresh,
+	}
+
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove the cache of the current workspace",
+		Args:  cobra.NoArgs,
+		RunE:  runCacheClear,
+	}
+	clearCmd.Flags().BoolVar(&cacheClearAll, "all", false, "Remove the cache of every workspace")
+
+	cmd.AddCommand(refreshCmd)
+	cmd.AddCommand(clearCmd)
+
+	return cmd
+}
+
+func newCacheClient() (*slack.Client, error) {
+	cfg, err := config.Load(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if token != "" {
+		cfg.Token = token
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return slack.NewClient(cfg.Token), nil
+}
+
+func runCacheRefresh(cmd *cobra.Command, args []string) error {
+	client, err := newCacheClient()
+	if err != nil {
+		return err
+	}
+
+	dir, err := openDirectory(client)
+	if err != nil {
+		return fmt.Errorf("failed to open cache: %w", err)
+	}
+	client.SetDirectory(dir)
+
+	channels, err := client.GetChannels()
+	if err != nil {
+		return fmt.Errorf("failed to fetch channels: %w", err)
+	}
+
+	users, err := client.GetUsers()
+	if err != nil {
+		return fmt.Errorf("failed to fetch users: %w", err)
+	}
+
+	if err := dir.Save(); err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+
+	fmt.Printf("[INFO] Cached %d channels and %d users\n", len(channels), len(users))
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	if cacheClearAll {
+		root, err := cache.Root()
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(root); err != nil {
+			return fmt.Errorf("failed to remove cache: %w", err)
+		}
+		fmt.Printf("[INFO] Removed %s\n", root)
+		return nil
+	}
+
+	client, err := newCacheClient()
+	if err != nil {
+		return err
+	}
+
+	dir, err := openDirectory(client)
+	if err != nil {
+		return fmt.Errorf("failed to open cache: %w", err)
+	}
+
+	if err := dir.Clear(); err != nil {
+		return err
+	}
+
+	fmt.Println("[INFO] Cache cleared")
+	return nil
+}
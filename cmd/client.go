@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/longkey1/slago/internal/cache"
+	"github.com/longkey1/slago/internal/config"
+	"github.com/longkey1/slago/internal/slack"
+)
+
+// newSlackClient creates a Slack client that reads channel and user lookups
+// through the workspace's directory cache. The returned function saves the cache.
+func newSlackClient(cfg *config.Config) (*slack.Client, func()) {
+	client := slack.NewClient(cfg.Token)
+	if noCache {
+		return client, func() {}
+	}
+
+	dir, err := openDirectory(client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] Directory cache disabled: %v\n", err)
+		return client, func() {}
+	}
+	client.SetDirectory(dir)
+
+	return client, func() {
+		if err := dir.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "[WARN] Failed to save directory cache: %v\n", err)
+		}
+	}
+}
+
+// openDirectory opens the directory cache of the token's workspace
+func openDirectory(client *slack.Client) (*cache.Directory, error) {
+	auth, err := client.AuthTest()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := cache.TeamDir(auth.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cache.Open(path, cacheTTL)
+}
+
+// listTarget is a workspace being collected, with the filters resolved
+// against it
+type listTarget struct {
+	client       slack.Service
+	author       string
+	mentions     []slack.MentionFilter
+	channels     []string
+	workspaceURL string
+	outputDir    string
+	location     *time.Location
+}
+
+// targetFlags are the filter flags of a command before they are resolved
+// against a workspace
+type targetFlags struct {
+	author         string
+	mentions       []string
+	mentionMembers bool
+	channels       []string
+}
+
+// resolveTarget resolves filter flags against the workspace of cfg. Empty
+// flags fall back to the profile's defaults. A nil tz becomes the token
+// user's Slack timezone, and stays nil when that cannot be read.
+func resolveTarget(client *slack.Client, cfg *config.Config, flags targetFlags, tz *time.Location) (listTarget, error) {
+	target := listTarget{
+		client:    client,
+		author:    flags.author,
+		channels:  flags.channels,
+		outputDir: cfg.OutputDir,
+		location:  tz,
+	}
+	if target.author == "" {
+		target.author = cfg.Author
+	}
+	if len(target.channels) == 0 {
+		target.channels = cfg.Channels
+	}
+	mentions := flags.mentions
+	if len(mentions) == 0 {
+		mentions = cfg.Mention
+	}
+
+	// Resolve the author reference (ID, @handle, email or "me") to a user ID
+	if target.author != "" {
+		user, err := client.ResolveUser(target.author)
+		if err != nil {
+			return listTarget{}, fmt.Errorf("failed to resolve author %q: %w", target.author, err)
+		}
+		target.author = user.ID
+	}
+
+	// Resolve user group handles in --mention to subteam IDs
+	target.mentions = client.ResolveMentions(mentions, flags.mentionMembers)
+
+	// Permalinks are built from the workspace URL instead of one API call each
+	if auth, err := client.AuthTest(); err != nil {
+		fmt.Printf("[WARN] Failed to get workspace URL, permalinks are omitted: %v\n", err)
+	} else {
+		target.workspaceURL = auth.URL
+	}
+
+	if target.location == nil {
+		if loc, err := client.Timezone(); err != nil {
+			fmt.Printf("[WARN] Failed to get the Slack timezone, using the default day boundaries: %v\n", err)
+		} else {
+			target.location = loc
+		}
+	}
+	return target, nil
+}
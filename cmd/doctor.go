@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/longkey1/slago/internal/config"
+	"github.com/longkey1/slago/internal/doctor"
+	"github.com/longkey1/slago/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the API token, its scopes and the connection to Slack",
+		Long: `Check the API token, its scopes and the connection to Slack.
+
+Calls auth.test, reports the token type, user and team, lists the scopes
+granted to the token and compares them with what each command and flag
+needs. Missing scopes come with advice on how to add them. Exits with an
+error when the token does not work or a required scope is missing.
+
+Examples:
+  slago doctor
+  slago doctor --token xoxb-...`,
+		Args: cobra.NoArgs,
+		RunE: runDoctor,
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(profile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if token != "" {
+		cfg.Token = token
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	tokenType, rotating := slack.TokenType(cfg.Token)
+	kind := tokenType + " token"
+	if rotating {
+		kind = "rotating " + kind
+	}
+	fmt.Printf("Token:     %s\n", kind)
+
+	client := slack.NewClient(cfg.Token)
+	auth, err := client.AuthTest()
+	if err != nil {
+		fmt.Printf("[FAIL] %v\n", err)
+		fmt.Printf("       %s\n", doctor.ErrorAdvice(tokenType, rotating, err))
+		return fmt.Errorf("token check failed")
+	}
+	fmt.Printf("User:      %s (%s)\n", auth.User, auth.UserID)
+	fmt.Printf("Team:      %s (%s)\n", auth.Team, auth.TeamID)
+	fmt.Printf("Workspace: %s\n", auth.URL)
+
+	scopes, err := client.Scopes()
+	if err != nil {
+		return fmt.Errorf("failed to get scopes: %w", err)
+	}
+	if len(scopes) == 0 {
+		fmt.Println("Scopes:    (not reported for this token)")
+		return nil
+	}
+	fmt.Printf("Scopes:    %s\n\n", strings.Join(scopes, ", "))
+
+	results := doctor.Check(tokenType, scopes)
+	for _, r := range results {
+		status := "[OK]"
+		switch {
+		case !r.Granted && r.Optional:
+			status = "[WARN]"
+		case !r.Granted:
+			status = "[FAIL]"
+		}
+		fmt.Printf("%-7s %-17s %s\n", status, r.Scope, r.Usage)
+		if !r.Granted {
+			fmt.Printf("%-7s %-17s %s\n", "", "", r.Advice)
+		}
+	}
+
+	if n := doctor.MissingRequired(results); n > 0 {
+		return fmt.Errorf("%d required scope(s) missing", n)
+	}
+	return nil
+}
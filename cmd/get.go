@@ -6,11 +6,17 @@ import (
 	"github.com/longkey1/slago/internal/collector"
 	"github.com/longkey1/slago/internal/config"
 	"github.com/longkey1/slago/internal/output"
-	"github.com/longkey1/slago/internal/slack"
 	"github.com/spf13/cobra"
 )
 
-var getWithThread bool
+var (
+	getWithThread    bool
+	getResolveUsers  bool
+	getDownloadFiles bool
+	getDownloadDir   string
+	getMaxFileSize   int64
+	getRawBlocks     bool
+)
 
 func newGetCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -20,12 +26,18 @@ func newGetCmd() *cobra.Command {
 
 Examples:
   slago get "https://xxx.slack.com/archives/C123/p456"
-  slago get "https://xxx.slack.com/archives/C123/p456" --thread`,
+  slago get "https://xxx.slack.com/archives/C123/p456" --thread
+  slago get "https://xxx.slack.com/archives/C123/p456" --download-files --download-dir out`,
 		Args: cobra.ExactArgs(1),
 		RunE: runGet,
 	}
 
 	cmd.Flags().BoolVar(&getWithThread, "thread", false, "Get the entire thread")
+	cmd.Flags().BoolVar(&getResolveUsers, "resolve-users", true, "Resolve author and mention user IDs to names")
+	cmd.Flags().BoolVar(&getRawBlocks, "raw-blocks", false, "Keep the raw Block Kit blocks of each message")
+	cmd.Flags().BoolVar(&getDownloadFiles, "download-files", false, "Download shared files into <download-dir>/files")
+	cmd.Flags().StringVar(&getDownloadDir, "download-dir", ".", "Directory downloaded files are saved under")
+	cmd.Flags().Int64Var(&getMaxFileSize, "max-file-size", collector.DefaultMaxFileSize>>20, "Skip downloading files larger than this many MiB (0 for no limit)")
 
 	return cmd
 }
@@ -34,7 +46,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 	url := args[0]
 
 	// Load config
-	cfg, err := config.Load()
+	cfg, err := config.Load(profile)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -49,12 +61,19 @@ func runGet(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create Slack client
-	client := slack.NewClient(cfg.Token)
+	client, saveCache := newSlackClient(cfg)
+	defer saveCache()
 
 	// Get message/thread
 	opts := collector.GetOptions{
-		URL:        url,
-		WithThread: getWithThread,
+		URL:          url,
+		WithThread:   getWithThread,
+		ResolveUsers: getResolveUsers,
+		RawBlocks:    getRawBlocks,
+	}
+	if getDownloadFiles {
+		opts.DownloadDir = getDownloadDir
+		opts.MaxFileSize = getMaxFileSize << 20
 	}
 
 	result, err := collector.Get(client, opts)
@@ -2,14 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/longkey1/slago/internal/collector"
 	"github.com/longkey1/slago/internal/config"
-	"github.com/longkey1/slago/internal/output"
-	"github.com/longkey1/slago/internal/slack"
 	"github.com/longkey1/slago/internal/dateutil"
+	"github.com/longkey1/slago/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +25,26 @@ var (
 	listChannels        []string
 	listExcludeChannels []string
 	listParallel        int
+	listResolveUsers    bool
+	listMentionMembers  bool
+	listSource          string
+	listIncludeDMs      bool
+	listIncludeMPDMs    bool
+	listReactions       bool
+	listHasReactions    []string
+	listFiles           bool
+	listDownloadFiles   bool
+	listMaxFileSize     int64
+	listRawBlocks       bool
+	listExcludeBots     bool
+	listExcludeSubtypes []string
+	listAllProfiles     bool
+	listQuery           string
+	listKeywords        []string
+	listHas             []string
+	listShowQuery       bool
+	listTZ              string
+	listLocalTime       bool
 )
 
 func newListCmd() *cobra.Command {
@@ -32,7 +53,15 @@ func newListCmd() *cobra.Command {
 		Short: "Collect messages for a date range and save to files",
 		Long: `Collect Slack messages for a date range and save to JSON files.
 
-Output is saved to logs/YYYY/MM/DD/slack.json for each day.
+Output is saved to logs/YYYY/MM/DD/slack.json for each day, or with a
+profile to logs/<profile>/YYYY/MM/DD/slack.json (or its output_dir). With
+--all-profiles every profile of the config file is collected in turn.
+
+Sources:
+  search   Use search.messages (default, requires a user token)
+  history  Walk conversations.history of the selected channels (works with
+           bot tokens; without --channel every channel the token is a member
+           of is used, and --thread expands replies)
 
 Date range options (mutually exclusive):
   --day      Single day (YYYY-MM-DD)
@@ -44,9 +73,25 @@ Examples:
   slago list --month 2025-01
   slago list --from 2025-01-01 --to 2025-01-15
   slago list -m 2025-01 --thread --author U12345678
+  slago list -d 2025-01-15 --author me
+  slago list -d 2025-01-15 --author @john.doe
+  slago list -d 2025-01-15 --author john@example.com
   slago list -d 2025-01-15 --mention U111 --mention @team
+  slago list -d 2025-01-15 --mention @team-backend --mention-members
   slago list -m 2025-01 --channel general --channel random
-  slago list -d 2025-01-15 --exclude-channel announcements`,
+  slago list -d 2025-01-15 --exclude-channel announcements
+  slago list -d 2025-01-15 --source history --channel alerts --thread
+  slago list -d 2025-01-15 --author me --include-dms --include-mpdms
+  slago list -d 2025-01-15 --has-reaction white_check_mark
+  slago list -d 2025-01-15 --reactions
+  slago list -d 2025-01-15 --files
+  slago list -d 2025-01-15 --download-files --max-file-size 10
+  slago list -d 2025-01-15 --exclude-bots --exclude-subtype channel_join,channel_leave
+  slago list -d 2025-01-15 --keyword deploy --keyword "release notes" --has link
+  slago list -d 2025-01-15 --query "is:thread -in:#random" --show-query
+  slago list -d 2025-01-15 --tz Asia/Tokyo --local-time
+  slago list -d 2025-01-15 --profile acme
+  slago list -d 2025-01-15 --all-profiles`,
 		RunE: runList,
 	}
 
@@ -55,35 +100,46 @@ Examples:
 	cmd.Flags().StringVar(&listFrom, "from", "", "Start date (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&listTo, "to", "", "End date (YYYY-MM-DD)")
 	cmd.Flags().BoolVar(&listThread, "thread", false, "Get entire threads")
-	cmd.Flags().StringVar(&listAuthor, "author", "", "Filter by author")
-	cmd.Flags().StringSliceVar(&listMentions, "mention", nil, "Filter by mention (comma-separated User IDs or @group-names)")
+	cmd.Flags().StringVar(&listAuthor, "author", "", "Filter by author (user ID, @handle, email or \"me\")")
+	cmd.Flags().StringSliceVar(&listMentions, "mention", nil, "Filter by mention (comma-separated user IDs, @handles, emails or @group-names)")
+	cmd.Flags().BoolVar(&listMentionMembers, "mention-members", false, "Also match mentions of the members of a --mention user group")
 	cmd.Flags().StringSliceVar(&listChannels, "channel", nil, "Filter by channel (comma-separated channel names)")
 	cmd.Flags().StringSliceVar(&listExcludeChannels, "exclude-channel", nil, "Exclude channels (comma-separated channel names)")
 	cmd.Flags().IntVarP(&listParallel, "parallel", "p", 1, "Number of parallel workers")
+	cmd.Flags().BoolVar(&listResolveUsers, "resolve-users", true, "Resolve author and mention user IDs to names")
+	cmd.Flags().BoolVar(&listIncludeDMs, "include-dms", false, "Also collect direct messages")
+	cmd.Flags().BoolVar(&listIncludeMPDMs, "include-mpdms", false, "Also collect group direct messages")
+	cmd.Flags().BoolVar(&listReactions, "reactions", false, "Fetch reactions of search matches outside threads (one extra API call per match)")
+	cmd.Flags().StringSliceVar(&listHasReactions, "has-reaction", nil, "Filter by reaction emoji name (comma-separated, all must be present)")
+	cmd.Flags().BoolVar(&listFiles, "files", false, "Record the shared files of search matches outside threads (one extra API call per match)")
+	cmd.Flags().BoolVar(&listDownloadFiles, "download-files", false, "Download shared files next to each day's slack.json")
+	cmd.Flags().Int64Var(&listMaxFileSize, "max-file-size", collector.DefaultMaxFileSize>>20, "Skip downloading files larger than this many MiB (0 for no limit)")
+	cmd.Flags().BoolVar(&listExcludeBots, "exclude-bots", false, "Exclude messages posted by bots and integrations")
+	cmd.Flags().StringSliceVar(&listExcludeSubtypes, "exclude-subtype", nil, "Exclude message subtypes (comma-separated, e.g. channel_join,channel_leave)")
+	cmd.Flags().BoolVar(&listRawBlocks, "raw-blocks", false, "Keep the raw Block Kit blocks of each message")
+	cmd.Flags().StringSliceVar(&listKeywords, "keyword", nil, "Filter by keyword (repeatable, comma-separated; keywords with spaces match as phrases)")
+	cmd.Flags().StringSliceVar(&listHas, "has", nil, "Filter with has: modifiers such as link, pin or reaction (comma-separated)")
+	cmd.Flags().StringVar(&listQuery, "query", "", "Extra terms in Slack's search syntax, added to the generated query")
+	cmd.Flags().BoolVar(&listShowQuery, "show-query", false, "Print the search query of each day")
+	cmd.Flags().StringVar(&listTZ, "tz", "", "Timezone of day boundaries, e.g. Asia/Tokyo (default: the token user's Slack timezone)")
+	cmd.Flags().BoolVar(&listLocalTime, "local-time", false, "Render timestamps in the --tz timezone instead of UTC")
+	cmd.Flags().BoolVar(&listAllProfiles, "all-profiles", false, "Collect every profile of the config file into logs/<profile>")
+	cmd.Flags().StringVar(&listSource, "source", collector.SourceSearch, "Collection source: search (user token) or history (works with bot tokens)")
 
 	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	// Load config
-	cfg, err := config.Load()
+	cfgs, err := loadListConfigs()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
 
-	// Override from flags
-	if token != "" {
-		cfg.Token = token
-	}
-	if listAuthor == "" {
-		listAuthor = cfg.Author
+	if listSource != collector.SourceSearch && listSource != collector.SourceHistory {
+		return fmt.Errorf("invalid --source %q: use %s or %s", listSource, collector.SourceSearch, collector.SourceHistory)
 	}
-	if len(listMentions) == 0 {
-		listMentions = cfg.Mention
-	}
-
-	if err := cfg.Validate(); err != nil {
-		return err
+	if listSource == collector.SourceHistory && (listQuery != "" || len(listKeywords) > 0 || len(listHas) > 0 || listShowQuery) {
+		return fmt.Errorf("--query, --keyword, --has and --show-query need --source %s", collector.SourceSearch)
 	}
 
 	// Parse date range
@@ -92,19 +148,100 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create Slack client
-	client := slack.NewClient(cfg.Token)
-
 	// Get all days to process
 	days := dateRange.Days()
 	if len(days) == 0 {
 		return fmt.Errorf("no days to process")
 	}
 
+	var tz *time.Location
+	if listTZ != "" {
+		if tz, err = time.LoadLocation(listTZ); err != nil {
+			return fmt.Errorf("invalid --tz %q: %w", listTZ, err)
+		}
+	}
+
+	if !listAllProfiles {
+		return listWorkspace(cfgs[0], days, tz)
+	}
+
+	// Collect every workspace, reporting failures at the end
+	var failed []string
+	for _, cfg := range cfgs {
+		fmt.Printf("[INFO] Profile %s: saving to %s\n", cfg.Profile, cfg.OutputDir)
+		if err := listWorkspace(cfg, days, tz); err != nil {
+			fmt.Printf("[ERROR] Profile %s: %v\n", cfg.Profile, err)
+			failed = append(failed, cfg.Profile)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d profile(s) failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// loadListConfigs loads the profile to collect, or every profile with
+// --all-profiles
+func loadListConfigs() ([]*config.Config, error) {
+	if !listAllProfiles {
+		cfg, err := config.Load(profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		// Override from flags
+		if token != "" {
+			cfg.Token = token
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return []*config.Config{cfg}, nil
+	}
+
+	if token != "" || profile != "" {
+		return nil, fmt.Errorf("--all-profiles cannot be combined with --token or --profile")
+	}
+	cfgs, err := config.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, cfg := range cfgs {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return cfgs, nil
+}
+
+// listWorkspace collects the days from the workspace of a config. Days
+// start at midnight in tz, or in the token user's Slack timezone.
+func listWorkspace(cfg *config.Config, days []time.Time, tz *time.Location) error {
+	// Create Slack client
+	client, saveCache := newSlackClient(cfg)
+	defer saveCache()
+
+	target, err := resolveTarget(client, cfg, targetFlags{
+		author:         listAuthor,
+		mentions:       listMentions,
+		mentionMembers: listMentionMembers,
+		channels:       listChannels,
+	}, tz)
+	if err != nil {
+		return err
+	}
+
+	// Preload the user directory once instead of looking users up day by day
+	if listResolveUsers && len(days) > 1 {
+		if _, err := client.GetUsers(); err != nil {
+			fmt.Printf("[WARN] Failed to preload users: %v\n", err)
+		}
+	}
+
 	fmt.Printf("Collecting messages for %d day(s)...\n", len(days))
 
 	// Process days with parallelism
-	results := processdays(client, days, listParallel)
+	results := processdays(target, days, listParallel)
 
 	// Report results
 	var errors []error
@@ -115,7 +252,7 @@ func runList(cmd *cobra.Command, args []string) error {
 			fmt.Printf("[INFO] %s: %d threads collected, saved to %s\n",
 				dateutil.FormatDate(result.Date),
 				len(result.Threads),
-				dateutil.OutputPath(result.Date))
+				dateutil.OutputPath(target.outputDir, result.Date))
 		}
 	}
 
@@ -168,7 +305,7 @@ func parseDateRange() (dateutil.DateRange, error) {
 	return dateutil.DateRange{}, fmt.Errorf("--from and --to must both be specified")
 }
 
-func processdays(client *slack.Client, days []time.Time, parallel int) []collector.DayResult {
+func processdays(target listTarget, days []time.Time, parallel int) []collector.DayResult {
 	if parallel < 1 {
 		parallel = 1
 	}
@@ -190,7 +327,7 @@ func processdays(client *slack.Client, days []time.Time, parallel int) []collect
 		go func() {
 			defer wg.Done()
 			for day := range work {
-				result := processDay(client, day)
+				result := processDay(target, day)
 				results <- result
 			}
 		}()
@@ -211,17 +348,38 @@ func processdays(client *slack.Client, days []time.Time, parallel int) []collect
 	return allResults
 }
 
-func processDay(client *slack.Client, day time.Time) collector.DayResult {
+func processDay(target listTarget, day time.Time) collector.DayResult {
 	opts := collector.ListOptions{
 		Date:            day,
-		Author:          listAuthor,
-		Mentions:        listMentions,
-		Channels:        listChannels,
+		Author:          target.author,
+		Mentions:        target.mentions,
+		Channels:        target.channels,
 		ExcludeChannels: listExcludeChannels,
 		WithThread:      listThread,
+		ResolveUsers:    listResolveUsers,
+		Source:          listSource,
+		IncludeDMs:      listIncludeDMs,
+		IncludeMPDMs:    listIncludeMPDMs,
+		Reactions:       listHasReactions,
+		WithReactions:   listReactions,
+		WithFiles:       listFiles,
+		RawBlocks:       listRawBlocks,
+		ExcludeBots:     listExcludeBots,
+		ExcludeSubtypes: listExcludeSubtypes,
+		WorkspaceURL:    target.workspaceURL,
+		Keywords:        listKeywords,
+		Has:             listHas,
+		Query:           listQuery,
+		ShowQuery:       listShowQuery,
+		Location:        target.location,
+		LocalTime:       listLocalTime,
+	}
+	if listDownloadFiles {
+		opts.DownloadDir = filepath.Dir(dateutil.OutputPath(target.outputDir, day))
+		opts.MaxFileSize = listMaxFileSize << 20
 	}
 
-	result, err := collector.List(client, opts)
+	result, err := collector.List(target.client, opts)
 	if err != nil {
 		return collector.DayResult{
 			Date:  day,
@@ -230,7 +388,7 @@ func processDay(client *slack.Client, day time.Time) collector.DayResult {
 	}
 
 	// Write to file
-	outputPath := dateutil.OutputPath(day)
+	outputPath := dateutil.OutputPath(target.outputDir, day)
 	writer, err := output.NewFileWriter(outputPath)
 	if err != nil {
 		return collector.DayResult{
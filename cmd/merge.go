@@ -12,9 +12,13 @@ import (
 )
 
 var (
-	mergeDir       string
-	mergePattern   string
-	mergeRecursive bool
+	mergeDir             string
+	mergePattern         string
+	mergeRecursive       bool
+	mergeRevisions       bool
+	mergeExcludeBots     bool
+	mergeExcludeSubtypes []string
+	mergeWorkspaceURL    string
 )
 
 func newMergeCmd() *cobra.Command {
@@ -24,8 +28,16 @@ func newMergeCmd() *cobra.Command {
 		Long: `Merge multiple JSON files from a directory, deduplicate threads and messages,
 and output the result to stdout.
 
-Thread deduplication: Threads with the same ThreadID are merged.
-Message deduplication: Messages with the same ID keep the one with the latest timestamp.
+Thread deduplication: Threads with the same channel and ThreadID are merged;
+timestamps may repeat across channels.
+Message deduplication: Messages with the same channel and ID keep the most
+recently edited copy, or the most recently collected one when no copy was
+edited later.
+Search copies carry no edit time: one collected after another copy was
+edited is kept.
+With --keep-revisions the other texts are kept as the message's revisions.
+Messages without a permalink get one built from the workspace URL of the
+input's permalinks, or from --workspace-url.
 
 Examples:
   slago merge ./logs
@@ -33,7 +45,10 @@ Examples:
   slago merge ./logs --pattern "slack*.json"
   slago merge ./logs -p "2025-*.json"
   slago merge ./logs --recursive
-  slago merge ./logs -r -p "*.json"`,
+  slago merge ./logs -r -p "*.json"
+  slago merge ./logs -r --keep-revisions
+  slago merge ./logs -r --exclude-bots --exclude-subtype channel_join,channel_leave
+  slago merge ./logs -r --workspace-url https://xxx.slack.com/`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: runMerge,
 	}
@@ -41,6 +56,10 @@ Examples:
 	cmd.Flags().StringVarP(&mergeDir, "dir", "d", "", "Target directory")
 	cmd.Flags().StringVarP(&mergePattern, "pattern", "p", "*.json", "File name glob pattern")
 	cmd.Flags().BoolVarP(&mergeRecursive, "recursive", "r", false, "Search subdirectories recursively")
+	cmd.Flags().BoolVar(&mergeExcludeBots, "exclude-bots", false, "Exclude messages posted by bots and integrations")
+	cmd.Flags().StringSliceVar(&mergeExcludeSubtypes, "exclude-subtype", nil, "Exclude message subtypes (comma-separated, e.g. channel_join,channel_leave)")
+	cmd.Flags().BoolVar(&mergeRevisions, "keep-revisions", false, "Keep earlier texts of edited messages as revisions")
+	cmd.Flags().StringVar(&mergeWorkspaceURL, "workspace-url", "", "Workspace URL for building missing permalinks (default: taken from the input)")
 
 	return cmd
 }
@@ -96,13 +115,21 @@ func runMerge(cmd *cobra.Command, args []string) error {
 
 	// Merge threads
 	result := collector.Merge(collector.MergeOptions{
-		Threads: allThreads,
+		Threads:         allThreads,
+		KeepRevisions:   mergeRevisions,
+		ExcludeBots:     mergeExcludeBots,
+		ExcludeSubtypes: mergeExcludeSubtypes,
+		WorkspaceURL:    mergeWorkspaceURL,
 	})
 
 	fmt.Fprintf(os.Stderr, "Merged: %d threads -> %d threads (%d duplicates removed)\n",
 		result.OriginalThreadCount, result.MergedThreadCount, result.DuplicateThreads)
 	fmt.Fprintf(os.Stderr, "Merged: %d messages -> %d messages (%d duplicates removed)\n",
 		result.OriginalMessageCount, result.MergedMessageCount, result.DuplicateMessages)
+	if result.ExcludedMessages > 0 {
+		fmt.Fprintf(os.Stderr, "Excluded: %d messages (%d threads left empty)\n",
+			result.ExcludedMessages, result.ExcludedThreads)
+	}
 
 	// Output to stdout
 	writer := output.NewStdoutWriter()
@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"time"
+
+	"github.com/longkey1/slago/internal/cache"
 	"github.com/spf13/cobra"
 )
 
-var token string
+var (
+	token    string
+	profile  string
+	noCache  bool
+	cacheTTL time.Duration
+)
 
 // NewRootCmd creates the root command
 func NewRootCmd() *cobra.Command {
@@ -16,18 +24,26 @@ func NewRootCmd() *cobra.Command {
 It supports:
   - Fetching messages by URL
   - Collecting messages for date ranges
+  - Syncing new messages and capturing them in real time
   - Filtering by author and mentions
   - Thread expansion`,
 	}
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "Slack API token (overrides SLACK_API_TOKEN)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Config file profile to use (overrides SLAGO_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the directory cache")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", cache.DefaultTTL, "How long cached channels and users are trusted")
 
 	// Add subcommands
+	rootCmd.AddCommand(newCacheCmd())
+	rootCmd.AddCommand(newDoctorCmd())
 	rootCmd.AddCommand(newGetCmd())
 	rootCmd.AddCommand(newListCmd())
 	rootCmd.AddCommand(newMergeCmd())
+	rootCmd.AddCommand(newSyncCmd())
 	rootCmd.AddCommand(newVersionCmd())
+	rootCmd.AddCommand(newWatchCmd())
 
 	return rootCmd
 }
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/longkey1/slago/internal/collector"
+	"github.com/longkey1/slago/internal/config"
+	"github.com/longkey1/slago/internal/dateutil"
+	"github.com/longkey1/slago/internal/input"
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncThread          bool
+	syncAuthor          string
+	syncMentions        []string
+	syncMentionMembers  bool
+	syncChannels        []string
+	syncExcludeChannels []string
+	syncResolveUsers    bool
+	syncSource          string
+	syncIncludeDMs      bool
+	syncIncludeMPDMs    bool
+	syncExcludeBots     bool
+	syncExcludeSubtypes []string
+	syncTZ              string
+	syncLocalTime       bool
+	syncSince           time.Duration
+	syncThreadLookback  time.Duration
+	syncState           string
+)
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Fetch messages posted since the last run and merge them into the day files",
+		Long: `Fetch only the messages posted since the last run and merge them into
+the existing day files (logs/YYYY/MM/DD/slack.json).
+
+The newest message seen by each search query, or in each channel with
+--source history, is stored in a state file together with the time of the
+run. The first run starts --since ago. With --thread, threads are placed in
+the day they started, and threads started within --thread-lookback are
+fetched again when their latest reply changed, even when the new replies do
+not match the search.
+
+Examples:
+  slago sync --author me --thread
+  slago sync --mention @team-backend --mention-members --since 72h
+  slago sync --source history --channel alerts --thread
+  slago sync --source history --thread --thread-lookback 336h
+  slago sync --author me --state ~/.local/state/slago/me.json`,
+		RunE: runSync,
+	}
+
+	cmd.Flags().BoolVar(&syncThread, "thread", false, "Get entire threads, and refetch threads with new replies")
+	cmd.Flags().StringVar(&syncAuthor, "author", "", "Filter by author (user ID, @handle, email or \"me\")")
+	cmd.Flags().StringSliceVar(&syncMentions, "mention", nil, "Filter by mention (comma-separated user IDs, @handles, emails or @group-names)")
+	cmd.Flags().BoolVar(&syncMentionMembers, "mention-members", false, "Also match mentions of the members of a --mention user group")
+	cmd.Flags().StringSliceVar(&syncChannels, "channel", nil, "Filter by channel (comma-separated channel names)")
+	cmd.Flags().StringSliceVar(&syncExcludeChannels, "exclude-channel", nil, "Exclude channels (comma-separated channel names)")
+	cmd.Flags().BoolVar(&syncResolveUsers, "resolve-users", true, "Resolve author and mention user IDs to names")
+	cmd.Flags().BoolVar(&syncIncludeDMs, "include-dms", false, "Also collect direct messages")
+	cmd.Flags().BoolVar(&syncIncludeMPDMs, "include-mpdms", false, "Also collect group direct messages")
+	cmd.Flags().BoolVar(&syncExcludeBots, "exclude-bots", false, "Exclude messages posted by bots and integrations")
+	cmd.Flags().StringSliceVar(&syncExcludeSubtypes, "exclude-subtype", nil, "Exclude message subtypes (comma-separated, e.g. channel_join,channel_leave)")
+	cmd.Flags().StringVar(&syncTZ, "tz", "", "Timezone of day files, e.g. Asia/Tokyo (default: the token user's Slack timezone)")
+	cmd.Flags().BoolVar(&syncLocalTime, "local-time", false, "Render timestamps in the --tz timezone instead of UTC")
+	cmd.Flags().DurationVar(&syncSince, "since", 24*time.Hour, "How far back the first run starts")
+	cmd.Flags().DurationVar(&syncThreadLookback, "thread-lookback", 7*24*time.Hour, "How long threads are watched for new replies (with --thread)")
+	cmd.Flags().StringVar(&syncState, "state", "", "State file (default: $XDG_STATE_HOME/slago/<profile>.json)")
+	cmd.Flags().StringVar(&syncSource, "source", collector.SourceSearch, "Collection source: search (user token) or history (works with bot tokens)")
+
+	return cmd
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(profile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if token != "" {
+		cfg.Token = token
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if syncSource != collector.SourceSearch && syncSource != collector.SourceHistory {
+		return fmt.Errorf("invalid --source %q: use %s or %s", syncSource, collector.SourceSearch, collector.SourceHistory)
+	}
+
+	var tz *time.Location
+	if syncTZ != "" {
+		if tz, err = time.LoadLocation(syncTZ); err != nil {
+			return fmt.Errorf("invalid --tz %q: %w", syncTZ, err)
+		}
+	}
+
+	statePath := syncState
+	if statePath == "" {
+		if statePath, err = defaultStatePath(cfg.Profile); err != nil {
+			return err
+		}
+	}
+	state, err := collector.LoadSyncState(statePath)
+	if err != nil {
+		return err
+	}
+
+	client, saveCache := newSlackClient(cfg)
+	defer saveCache()
+
+	target, err := resolveTarget(client, cfg, targetFlags{
+		author:         syncAuthor,
+		mentions:       syncMentions,
+		mentionMembers: syncMentionMembers,
+		channels:       syncChannels,
+	}, tz)
+	if err != nil {
+		return err
+	}
+	// Day files need a zone
+	if target.location == nil {
+		target.location = time.UTC
+	}
+	tz = target.location
+
+	now := time.Now()
+	result, err := collector.Sync(client, collector.SyncOptions{
+		List: collector.ListOptions{
+			Author:          target.author,
+			Mentions:        target.mentions,
+			Channels:        target.channels,
+			ExcludeChannels: syncExcludeChannels,
+			WithThread:      syncThread,
+			ResolveUsers:    syncResolveUsers,
+			Source:          syncSource,
+			IncludeDMs:      syncIncludeDMs,
+			IncludeMPDMs:    syncIncludeMPDMs,
+			ExcludeBots:     syncExcludeBots,
+			ExcludeSubtypes: syncExcludeSubtypes,
+			WorkspaceURL:    target.workspaceURL,
+			Location:        tz,
+			LocalTime:       syncLocalTime,
+		},
+		State:          state,
+		Since:          now.Add(-syncSince),
+		ThreadLookback: syncThreadLookback,
+		Now:            now,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The state is saved only once every day file is written, so a failed
+	// run is fetched again
+	days := threadsByDay(result.Threads, tz)
+	for _, day := range sortedDays(days) {
+		path := dateutil.OutputPath(cfg.OutputDir, day)
+		merged, err := mergeDayFile(path, days[day], collector.MergeOptions{
+			ExcludeBots:     syncExcludeBots,
+			ExcludeSubtypes: syncExcludeSubtypes,
+			WorkspaceURL:    target.workspaceURL,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", dateutil.FormatDate(day), err)
+		}
+		fmt.Printf("[INFO] %s: %d threads synced, %d in %s\n", dateutil.FormatDate(day), len(days[day]), merged, path)
+	}
+
+	if err := state.Save(statePath); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] %d new messages, %d threads with new replies\n", result.NewMessages, result.RefetchedThreads)
+	return nil
+}
+
+// threadsByDay groups threads by the day they started in loc
+func threadsByDay(threads []model.Thread, loc *time.Location) map[time.Time][]model.Thread {
+	days := make(map[time.Time][]model.Thread)
+	for _, t := range threads {
+		start := model.TS(t.ThreadID).Time().In(loc)
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		days[day] = append(days[day], t)
+	}
+	return days
+}
+
+func sortedDays(days map[time.Time][]model.Thread) []time.Time {
+	sorted := make([]time.Time, 0, len(days))
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Before(sorted[j])
+	})
+	return sorted
+}
+
+// mergeDayFile merges threads into a day file, creating it if needed, and
+// returns the number of threads in the file
+func mergeDayFile(path string, threads []model.Thread, opts collector.MergeOptions) (int, error) {
+	existing, err := input.NewFileReader().ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	opts.Threads = append(existing, threads...)
+	result := collector.Merge(opts)
+
+	writer, err := output.NewFileWriter(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer writer.Close()
+
+	if err := writer.Write(result.Threads); err != nil {
+		return 0, fmt.Errorf("failed to write output: %w", err)
+	}
+	return len(result.Threads), nil
+}
+
+// defaultStatePath returns $XDG_STATE_HOME/slago/<profile>.json, outside the
+// output tree so that merge does not read it as a day file
+func defaultStatePath(profile string) (string, error) {
+	base := os.Getenv("XDG_STATE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate state directory: %w", err)
+		}
+		base = filepath.Join(home, ".local", "state")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return filepath.Join(base, "slago", profile+".json"), nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/longkey1/slago/internal/collector"
+	"github.com/longkey1/slago/internal/config"
+	"github.com/longkey1/slago/internal/dateutil"
+	"github.com/longkey1/slago/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchAppToken        string
+	watchThread          bool
+	watchAuthor          string
+	watchMentions        []string
+	watchMentionMembers  bool
+	watchChannels        []string
+	watchExcludeChannels []string
+	watchResolveUsers    bool
+	watchIncludeDMs      bool
+	watchIncludeMPDMs    bool
+	watchExcludeBots     bool
+	watchExcludeSubtypes []string
+	watchRawBlocks       bool
+	watchTZ              string
+	watchLocalTime       bool
+)
+
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Capture messages in real time over Socket Mode",
+		Long: `Receive messages over Socket Mode as they are posted or edited, and merge
+them into the file of the day their thread started
+(logs/YYYY/MM/DD/slack.json), as sync does, until interrupted.
+
+Socket Mode needs a Slack app with Socket Mode enabled, an app-level token
+(xapp-) with connections:write, set with --app-token or SLACK_APP_TOKEN, and
+event subscriptions for the message events to capture (message.channels,
+message.groups, message.im, message.mpim). The API token is still used to
+look up channels, threads and users.
+
+The filters work as in list. With --thread, the first matching message in a
+thread brings the whole thread, and later messages in it are kept. Dropped
+connections are reopened, waiting longer after each failed attempt.
+
+Examples:
+  slago watch --author me --thread
+  slago watch --channel alerts --channel incidents --exclude-bots
+  slago watch --mention @team-backend --mention-members
+  slago watch --app-token xapp-1-... --tz Asia/Tokyo`,
+		RunE: runWatch,
+	}
+
+	cmd.Flags().StringVar(&watchAppToken, "app-token", "", "Slack app-level token for Socket Mode (overrides SLACK_APP_TOKEN)")
+	cmd.Flags().BoolVar(&watchThread, "thread", false, "Get entire threads of matching messages")
+	cmd.Flags().StringVar(&watchAuthor, "author", "", "Filter by author (user ID, @handle, email or \"me\")")
+	cmd.Flags().StringSliceVar(&watchMentions, "mention", nil, "Filter by mention (comma-separated user IDs, @handles, emails or @group-names)")
+	cmd.Flags().BoolVar(&watchMentionMembers, "mention-members", false, "Also match mentions of the members of a --mention user group")
+	cmd.Flags().StringSliceVar(&watchChannels, "channel", nil, "Filter by channel (comma-separated channel names)")
+	cmd.Flags().StringSliceVar(&watchExcludeChannels, "exclude-channel", nil, "Exclude channels (comma-separated channel names)")
+	cmd.Flags().BoolVar(&watchResolveUsers, "resolve-users", true, "Resolve author and mention user IDs to names")
+	cmd.Flags().BoolVar(&watchIncludeDMs, "include-dms", false, "Also capture direct messages")
+	cmd.Flags().BoolVar(&watchIncludeMPDMs, "include-mpdms", false, "Also capture group direct messages")
+	cmd.Flags().BoolVar(&watchExcludeBots, "exclude-bots", false, "Exclude messages posted by bots and integrations")
+	cmd.Flags().StringSliceVar(&watchExcludeSubtypes, "exclude-subtype", nil, "Exclude message subtypes (comma-separated, e.g. channel_join,channel_leave)")
+	cmd.Flags().BoolVar(&watchRawBlocks, "raw-blocks", false, "Keep the raw Block Kit blocks of each message")
+	cmd.Flags().StringVar(&watchTZ, "tz", "", "Timezone of day files, e.g. Asia/Tokyo (default: the token user's Slack timezone)")
+	cmd.Flags().BoolVar(&watchLocalTime, "local-time", false, "Render timestamps in the --tz timezone instead of UTC")
+
+	return cmd
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load(profile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if token != "" {
+		cfg.Token = token
+	}
+	if watchAppToken != "" {
+		cfg.AppToken = watchAppToken
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.AppToken == "" {
+		return fmt.Errorf("slack app-level token is required (set SLACK_APP_TOKEN, app_token in the profile, or use --app-token flag)")
+	}
+
+	var tz *time.Location
+	if watchTZ != "" {
+		if tz, err = time.LoadLocation(watchTZ); err != nil {
+			return fmt.Errorf("invalid --tz %q: %w", watchTZ, err)
+		}
+	}
+
+	client, saveCache := newSlackClient(cfg)
+	defer saveCache()
+
+	target, err := resolveTarget(client, cfg, targetFlags{
+		author:         watchAuthor,
+		mentions:       watchMentions,
+		mentionMembers: watchMentionMembers,
+		channels:       watchChannels,
+	}, tz)
+	if err != nil {
+		return err
+	}
+	// Day files need a zone
+	if target.location == nil {
+		target.location = time.UTC
+	}
+	tz = target.location
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching messages, saving to %s (Ctrl-C to stop)...\n", cfg.OutputDir)
+
+	return collector.Watch(ctx, client, collector.WatchOptions{
+		List: collector.ListOptions{
+			Author:          target.author,
+			Mentions:        target.mentions,
+			Channels:        target.channels,
+			ExcludeChannels: watchExcludeChannels,
+			WithThread:      watchThread,
+			ResolveUsers:    watchResolveUsers,
+			IncludeDMs:      watchIncludeDMs,
+			IncludeMPDMs:    watchIncludeMPDMs,
+			RawBlocks:       watchRawBlocks,
+			ExcludeBots:     watchExcludeBots,
+			ExcludeSubtypes: watchExcludeSubtypes,
+			WorkspaceURL:    target.workspaceURL,
+			Location:        tz,
+			LocalTime:       watchLocalTime,
+		},
+		AppToken: cfg.AppToken,
+	}, func(threads []model.Thread) {
+		// A reply goes into the file of the day its thread started, as
+		// sync files it
+		days := threadsByDay(threads, tz)
+		for _, day := range sortedDays(days) {
+			path := dateutil.OutputPath(cfg.OutputDir, day)
+			if _, err := mergeDayFile(path, days[day], collector.MergeOptions{WorkspaceURL: target.workspaceURL}); err != nil {
+				fmt.Printf("[ERROR] Failed to save to %s: %v\n", path, err)
+				continue
+			}
+			for _, t := range days[day] {
+				fmt.Printf("[INFO] #%s: %d message(s) in thread %s saved to %s\n", t.Channel, len(t.Messages), t.ThreadID, path)
+			}
+		}
+	})
+}
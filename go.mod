@@ -3,12 +3,12 @@ module github.com/longkey1/slago
 go 1.25
 
 require (
+	github.com/gorilla/websocket v1.5.3
 	github.com/slack-go/slack v0.17.3
 	github.com/spf13/cobra v1.10.2
 )
 
 require (
-	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
 	github.com/stretchr/testify v1.11.1 // indirect
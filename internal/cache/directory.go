@@ -0,0 +1,205 @@
+// Package cache persists workspace directory data (channels and users) on disk
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+// DefaultTTL is how long cached entries are trusted before they are refetched
+const DefaultTTL = 24 * time.Hour
+
+const (
+	channelsFile = "channels.json"
+	usersFile    = "users.json"
+)
+
+type channelEntry struct {
+	model.Channel
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+type userEntry struct {
+	model.User
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Directory is an on-disk cache of the channels and users of one workspace
+type Directory struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	channels map[string]channelEntry
+	users    map[string]userEntry
+	dirty    bool
+}
+
+// Root returns the cache root, $XDG_CACHE_HOME/slago by default
+func Root() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return filepath.Join(base, "slago"), nil
+}
+
+// TeamDir returns the cache directory of a workspace
+func TeamDir(teamID string) (string, error) {
+	root, err := Root()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, teamID), nil
+}
+
+// Open loads the directory cache stored in dir. Missing files start empty.
+func Open(dir string, ttl time.Duration) (*Directory, error) {
+	d := &Directory{
+		dir:      dir,
+		ttl:      ttl,
+		now:      time.Now,
+		channels: make(map[string]channelEntry),
+		users:    make(map[string]userEntry),
+	}
+
+	if err := readJSON(filepath.Join(dir, channelsFile), &d.channels); err != nil {
+		return nil, err
+	}
+	if err := readJSON(filepath.Join(dir, usersFile), &d.users); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Channel returns a cached channel if it has not expired
+func (d *Directory) Channel(channelID string) (*model.Channel, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.channels[channelID]
+	if !ok || d.expired(entry.FetchedAt) {
+		return nil, false
+	}
+	ch := entry.Channel
+	return &ch, true
+}
+
+// PutChannel stores a channel
+func (d *Directory) PutChannel(ch model.Channel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.channels[ch.ID] = channelEntry{Channel: ch, FetchedAt: d.now()}
+	d.dirty = true
+}
+
+// User returns a cached user if it has not expired
+func (d *Directory) User(userID string) (*model.User, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.users[userID]
+	if !ok || d.expired(entry.FetchedAt) {
+		return nil, false
+	}
+	u := entry.User
+	return &u, true
+}
+
+// PutUser stores a user
+func (d *Directory) PutUser(u model.User) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.users[u.ID] = userEntry{User: u, FetchedAt: d.now()}
+	d.dirty = true
+}
+
+// Len returns the number of cached channels and users
+func (d *Directory) Len() (channels, users int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.channels), len(d.users)
+}
+
+// Save writes the cache to disk if anything changed
+func (d *Directory) Save() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := writeJSON(filepath.Join(d.dir, channelsFile), d.channels); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(d.dir, usersFile), d.users); err != nil {
+		return err
+	}
+
+	d.dirty = false
+	return nil
+}
+
+// Clear removes all cached entries, in memory and on disk
+func (d *Directory) Clear() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.channels = make(map[string]channelEntry)
+	d.users = make(map[string]userEntry)
+	d.dirty = false
+
+	if err := os.RemoveAll(d.dir); err != nil {
+		return fmt.Errorf("failed to remove cache directory: %w", err)
+	}
+	return nil
+}
+
+func (d *Directory) expired(fetchedAt time.Time) bool {
+	return d.ttl > 0 && d.now().Sub(fetchedAt) > d.ttl
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache file: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse cache file %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	// Write atomically so concurrent slago runs never read a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
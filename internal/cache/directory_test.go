@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+func TestDirectorySaveAndOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	d, err := Open(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	d.PutChannel(model.Channel{ID: "C1", Name: "general"})
+	d.PutUser(model.User{ID: "U1", Name: "alice", DisplayName: "Alice"})
+	if err := d.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reopened, err := Open(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if ch, ok := reopened.Channel("C1"); !ok || ch.Name != "general" {
+		t.Errorf("Channel(C1) = %v, %v, want general", ch, ok)
+	}
+	if u, ok := reopened.User("U1"); !ok || u.DisplayName != "Alice" {
+		t.Errorf("User(U1) = %v, %v, want Alice", u, ok)
+	}
+}
+
+func TestDirectoryTTL(t *testing.T) {
+	d, err := Open(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	now := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	d.PutChannel(model.Channel{ID: "C1", Name: "general"})
+
+	now = now.Add(30 * time.Minute)
+	if _, ok := d.Channel("C1"); !ok {
+		t.Errorf("Channel(C1) expired after 30m, want fresh")
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := d.Channel("C1"); ok {
+		t.Errorf("Channel(C1) fresh after 90m, want expired")
+	}
+}
+
+func TestDirectoryClear(t *testing.T) {
+	dir := t.TempDir()
+
+	d, err := Open(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	d.PutUser(model.User{ID: "U1", Name: "alice"})
+	if err := d.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := d.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+
+	reopened, err := Open(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if channels, users := reopened.Len(); channels != 0 || users != 0 {
+		t.Errorf("Len() after Clear = %d, %d, want 0, 0", channels, users)
+	}
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/mrkdwn"
+	"github.com/longkey1/slago/internal/slack"
+)
+
+// directoryLookup resolves mrkdwn user and channel references through the
+// client, remembering failures so that each ID is looked up once
+type directoryLookup struct {
+	client   slack.Service
+	users    map[string]string
+	channels map[string]string
+}
+
+func newDirectoryLookup(client slack.Service) *directoryLookup {
+	return &directoryLookup{
+		client:   client,
+		users:    make(map[string]string),
+		channels: make(map[string]string),
+	}
+}
+
+// UserName implements mrkdwn.Lookup
+func (l *directoryLookup) UserName(userID string) (string, bool) {
+	name, ok := l.users[userID]
+	if !ok {
+		if user, err := l.client.GetUser(userID); err == nil {
+			name = user.Label()
+		}
+		l.users[userID] = name
+	}
+	return name, name != ""
+}
+
+// ChannelName implements mrkdwn.Lookup
+func (l *directoryLookup) ChannelName(channelID string) (string, bool) {
+	name, ok := l.channels[channelID]
+	if !ok {
+		if ch, err := l.client.GetChannel(channelID); err == nil {
+			name = ch.Name
+		}
+		l.channels[channelID] = name
+	}
+	return name, name != ""
+}
+
+// renderContent fills the plain text and Markdown renderings of the content.
+// Markdown comes from the rich_text blocks when the message has them. IDs are
+// resolved to names only with resolve.
+func renderContent(client slack.Service, messages []model.Message, resolve bool) {
+	var lookup mrkdwn.Lookup = mrkdwn.NoLookup{}
+	if resolve {
+		lookup = newDirectoryLookup(client)
+	}
+
+	for i := range messages {
+		msg := &messages[i]
+		msg.ContentText = mrkdwn.ToText(msg.Content, lookup)
+		msg.ContentMarkdown = mrkdwn.RenderRawBlocks(msg.Blocks, lookup)
+		if msg.ContentMarkdown == "" {
+			msg.ContentMarkdown = mrkdwn.ToMarkdown(msg.Content, lookup)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack"
+)
+
+// describeConversations tags messages with their conversation type and labels
+// DMs and group DMs with the participants' names instead of the channel name
+func describeConversations(client slack.Service, messages []model.Message) {
+	labels := make(map[string]*model.Channel)
+
+	for i := range messages {
+		msg := &messages[i]
+		if msg.ChannelID == "" {
+			continue
+		}
+		if msg.ChannelType != "" && msg.ChannelType != model.ChannelTypeIM && msg.ChannelType != model.ChannelTypeMPIM {
+			continue
+		}
+
+		ch, ok := labels[msg.ChannelID]
+		if !ok {
+			ch = describeConversation(client, msg.ChannelID)
+			labels[msg.ChannelID] = ch
+		}
+		if ch == nil {
+			continue
+		}
+
+		msg.Channel = ch.Name
+		msg.ChannelType = ch.Type()
+		msg.IsPrivate = ch.Type() != model.ChannelTypePublic
+	}
+}
+
+func describeConversation(client slack.Service, channelID string) *model.Channel {
+	ch, err := client.GetChannel(channelID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] Could not get conversation info for %s: %v\n", channelID, err)
+		return nil
+	}
+
+	if !ch.IsDirect() {
+		return ch
+	}
+
+	labelled := *ch
+	if label := participantsLabel(client, ch); label != "" {
+		labelled.Name = label
+	}
+	return &labelled
+}
+
+// participantsLabel returns the names of the other participants of a DM or group DM
+func participantsLabel(client slack.Service, ch *model.Channel) string {
+	var participants []string
+	if ch.IsIM {
+		participants = []string{ch.User}
+	} else {
+		members, err := client.GetConversationMembers(ch.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[WARN] Could not get members of %s: %v\n", ch.ID, err)
+			return ""
+		}
+		participants = members
+	}
+
+	self := ""
+	if auth, err := client.AuthTest(); err == nil {
+		self = auth.UserID
+	}
+
+	var names []string
+	for _, userID := range participants {
+		if userID == "" || (userID == self && len(participants) > 1) {
+			continue
+		}
+		name := userID
+		if user, err := client.GetUser(userID); err == nil {
+			name = user.Label()
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestListIncludeDMs(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.SetAuth(slackapi.AuthTestResponse{UserID: "U0", User: "me"})
+	srv.AddUser(slackapi.User{ID: "U0", Name: "me"})
+	srv.AddUser(slackapi.User{ID: "U1", Name: "alice"})
+	srv.AddUser(slackapi.User{ID: "U2", Name: "bob"})
+
+	im := slackapi.Channel{}
+	im.ID = "D1"
+	im.IsIM = true
+	im.User = "U1"
+	srv.AddConversation(im)
+
+	mpim := slackapi.Channel{}
+	mpim.ID = "G1"
+	mpim.Name = "mpdm-me--alice--bob-1"
+	mpim.IsMpIM = true
+	mpim.IsPrivate = true
+	srv.AddConversation(mpim)
+	srv.SetMembers("G1", []string{"U0", "U1", "U2"})
+
+	srv.AddChannel("C1", "general")
+
+	srv.AddSearchMatch(newSearchMatch("D1", "U1", "1736935200.000100", "", "U1", "hi"))
+	mpimMatch := newSearchMatch("G1", "mpdm-me--alice--bob-1", "1736935260.000200", "", "U2", "hey all")
+	mpimMatch.Channel.IsMPIM = true
+	mpimMatch.Channel.IsPrivate = true
+	srv.AddSearchMatch(mpimMatch)
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935320.000300", "", "U1", "public"))
+
+	result, err := List(srv.Client(), ListOptions{
+		Date:         time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		IncludeDMs:   true,
+		IncludeMPDMs: true,
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	want := []struct {
+		channel     string
+		channelType string
+		isPrivate   bool
+	}{
+		{"alice", model.ChannelTypeIM, true},
+		{"alice, bob", model.ChannelTypeMPIM, true},
+		{"general", model.ChannelTypePublic, false},
+	}
+	if len(result.Threads) != len(want) {
+		t.Fatalf("List() threads = %d, want %d", len(result.Threads), len(want))
+	}
+	for i, w := range want {
+		got := result.Threads[i]
+		if got.Channel != w.channel || got.ChannelType != w.channelType || got.IsPrivate != w.isPrivate {
+			t.Errorf("thread %d = %q/%q/%v, want %q/%q/%v",
+				i, got.Channel, got.ChannelType, got.IsPrivate, w.channel, w.channelType, w.isPrivate)
+		}
+	}
+
+	query := srv.Queries()[0]
+	if strings.Contains(query, "-is:dm") || strings.Contains(query, "-is:mpdm") {
+		t.Errorf("query %q still excludes DMs", query)
+	}
+}
@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+// UserResolver looks up Slack user profiles by ID
+type UserResolver interface {
+	GetUser(userID string) (*model.User, error)
+}
+
+// EnrichUsers fills author profiles and mention names from the user directory.
+// Users that cannot be resolved are left as raw IDs.
+func EnrichUsers(resolver UserResolver, messages []model.Message) {
+	warned := false
+	lookup := func(userID string) *model.User {
+		user, err := resolver.GetUser(userID)
+		if err != nil {
+			if !warned {
+				fmt.Fprintf(os.Stderr, "[WARN] Failed to resolve user %s: %v\n", userID, err)
+				warned = true
+			}
+			return nil
+		}
+		return user
+	}
+
+	for i := range messages {
+		msg := &messages[i]
+
+		if msg.Author != "" {
+			if user := lookup(msg.Author); user != nil {
+				msg.AuthorName = user.Name
+				msg.AuthorDisplayName = user.DisplayName
+				msg.AuthorRealName = user.RealName
+			}
+		}
+
+		for j := range msg.Mentions {
+			mention := &msg.Mentions[j]
+			if mention.ID == "" || !mention.IsUser() {
+				continue
+			}
+			if user := lookup(mention.ID); user != nil {
+				mention.Name = user.Label()
+			}
+		}
+	}
+}
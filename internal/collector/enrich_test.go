@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestEnrichUsers(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	alice := slackapi.User{ID: "U1", Name: "alice"}
+	alice.Profile.DisplayName = "Alice"
+	alice.Profile.RealName = "Alice Liddell"
+	srv.AddUser(alice)
+	bob := slackapi.User{ID: "U2", Name: "bob"}
+	bob.Profile.RealName = "Bob Builder"
+	srv.AddUser(bob)
+
+	messages := []model.Message{
+		{
+			ID:       "1736935200.000100",
+			Author:   "U1",
+			Mentions: []model.Mention{{ID: "U2", Name: "bobby"}, {ID: "U9", Name: "ghost"}},
+		},
+		{
+			ID:     "1736935260.000200",
+			Author: "U1",
+		},
+	}
+
+	client := srv.Client()
+	EnrichUsers(client, messages)
+
+	got := messages[0]
+	if got.AuthorName != "alice" || got.AuthorDisplayName != "Alice" || got.AuthorRealName != "Alice Liddell" {
+		t.Errorf("author = %q/%q/%q, want alice/Alice/Alice Liddell",
+			got.AuthorName, got.AuthorDisplayName, got.AuthorRealName)
+	}
+	if got.Mentions[0].Name != "Bob Builder" {
+		t.Errorf("mention name = %q, want %q", got.Mentions[0].Name, "Bob Builder")
+	}
+	if got.Mentions[1].Name != "ghost" {
+		t.Errorf("unresolved mention name = %q, want original label %q", got.Mentions[1].Name, "ghost")
+	}
+	if calls := srv.Calls("users.info"); calls != 3 {
+		t.Errorf("users.info calls = %d, want 3 (one per distinct user)", calls)
+	}
+}
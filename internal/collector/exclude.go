@@ -0,0 +1,26 @@
+package collector
+
+import (
+	"slices"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+// excludeMessages drops bot messages and messages of the given subtypes
+func excludeMessages(messages []model.Message, bots bool, subtypes []string) []model.Message {
+	if !bots && len(subtypes) == 0 {
+		return messages
+	}
+
+	result := make([]model.Message, 0, len(messages))
+	for _, msg := range messages {
+		if bots && msg.IsBot() {
+			continue
+		}
+		if msg.Subtype != "" && slices.Contains(subtypes, msg.Subtype) {
+			continue
+		}
+		result = append(result, msg)
+	}
+	return result
+}
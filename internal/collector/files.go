@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+// DefaultMaxFileSize is the largest file downloaded by default (100 MiB)
+const DefaultMaxFileSize int64 = 100 << 20
+
+// filesDir is the directory, next to the JSON output, files are saved in
+const filesDir = "files"
+
+// FileDownloader downloads private Slack files
+type FileDownloader interface {
+	DownloadFile(url string, w io.Writer) error
+}
+
+var errFileTooLarge = errors.New("file exceeds the size limit")
+
+// DownloadFiles saves the files shared in messages under dir/files and
+// records where each one was saved. Files are named by their ID, so a file
+// shared twice or already downloaded by an earlier run is fetched once.
+// Files larger than maxSize are skipped; maxSize <= 0 disables the limit.
+func DownloadFiles(client FileDownloader, messages []model.Message, dir string, maxSize int64) {
+	saved := make(map[string]string)
+	for i := range messages {
+		for j := range messages[i].Files {
+			file := &messages[i].Files[j]
+			if path, ok := saved[file.ID]; ok {
+				file.LocalPath = path
+				continue
+			}
+
+			path, err := downloadFile(client, *file, dir, maxSize)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[WARN] Skipped file %s (%s): %v\n", file.ID, file.Name, err)
+				continue
+			}
+			file.LocalPath = path
+			saved[file.ID] = path
+		}
+	}
+}
+
+func downloadFile(client FileDownloader, file model.File, dir string, maxSize int64) (string, error) {
+	if file.URLPrivate == "" {
+		return "", fmt.Errorf("no download URL")
+	}
+	if maxSize > 0 && file.Size > maxSize {
+		return "", fmt.Errorf("%w (%d > %d bytes)", errFileTooLarge, file.Size, maxSize)
+	}
+
+	rel := filepath.Join(filesDir, fileName(file))
+	path := filepath.Join(dir, rel)
+	if info, err := os.Stat(path); err == nil && info.Size() == file.Size {
+		return filepath.ToSlash(rel), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+
+	// The reported size is not trusted to enforce the limit
+	var w io.Writer = f
+	if maxSize > 0 {
+		w = &limitedWriter{w: f, remaining: maxSize}
+	}
+	err = client.DownloadFile(file.URLPrivate, w)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// fileName builds a file system safe name that is unique per file ID
+func fileName(file model.File) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 0x20 {
+			return '_'
+		}
+		return r
+	}, file.Name)
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return file.ID
+	}
+	return file.ID + "-" + name
+}
+
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (lw *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > lw.remaining {
+		return 0, errFileTooLarge
+	}
+	n, err := lw.w.Write(p)
+	lw.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestListDownloadFiles(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	report := srv.AddFile(slackapi.File{ID: "F1", Name: "report.txt", Mimetype: "text/plain"}, []byte("quarterly numbers"))
+	large := srv.AddFile(slackapi.File{ID: "F2", Name: "dump.bin"}, make([]byte, 2048))
+
+	first := newMessage("1736935200.000100", "", "U1", "see attached")
+	first.Files = []slackapi.File{report, large}
+	srv.AddMessage("C1", first)
+	second := newMessage("1736938800.000200", "", "U2", "shared again")
+	second.Files = []slackapi.File{report}
+	srv.AddMessage("C1", second)
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935200.000100", "", "U1", "see attached"))
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736938800.000200", "", "U2", "shared again"))
+
+	dir := t.TempDir()
+	result, err := List(srv.Client(), ListOptions{
+		Date:        time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		DownloadDir: dir,
+		MaxFileSize: 1024,
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	var files []model.File
+	for _, msg := range result.Messages {
+		files = append(files, msg.Files...)
+	}
+	if len(files) != 3 {
+		t.Fatalf("files = %d, want 3", len(files))
+	}
+	for _, f := range files {
+		want := ""
+		if f.ID == "F1" {
+			want = "files/F1-report.txt"
+		}
+		if f.LocalPath != want {
+			t.Errorf("file %s local path = %q, want %q", f.ID, f.LocalPath, want)
+		}
+	}
+	if files[0].Mimetype != "text/plain" || files[0].Size != int64(len("quarterly numbers")) {
+		t.Errorf("file metadata = %+v", files[0])
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "files", "F1-report.txt"))
+	if err != nil {
+		t.Fatalf("downloaded file: %v", err)
+	}
+	if string(data) != "quarterly numbers" {
+		t.Errorf("downloaded content = %q", data)
+	}
+	if got := srv.Calls("files"); got != 1 {
+		t.Errorf("file downloads = %d, want 1", got)
+	}
+
+	// Files already on disk are not fetched again
+	DownloadFiles(srv.Client(), []model.Message{{Files: []model.File{files[0]}}}, dir, 0)
+	if got := srv.Calls("files"); got != 1 {
+		t.Errorf("file downloads after rerun = %d, want 1", got)
+	}
+}
+
+func TestListRecordsFilesWithoutDownload(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	report := srv.AddFile(slackapi.File{ID: "F1", Name: "report.txt"}, []byte("quarterly numbers"))
+	msg := newMessage("1736935200.000100", "", "U1", "see attached")
+	msg.Files = []slackapi.File{report}
+	srv.AddMessage("C1", msg)
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935200.000100", "", "U1", "see attached"))
+
+	result, err := List(srv.Client(), ListOptions{
+		Date:      time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		WithFiles: true,
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(result.Messages) != 1 || len(result.Messages[0].Files) != 1 {
+		t.Fatalf("messages = %+v, want one message with one file", result.Messages)
+	}
+	if f := result.Messages[0].Files[0]; f.ID != "F1" || f.Name != "report.txt" || f.LocalPath != "" {
+		t.Errorf("file = %+v, want F1 recorded without a local path", f)
+	}
+	if got := srv.Calls("files"); got != 0 {
+		t.Errorf("file downloads = %d, want 0", got)
+	}
+}
+
+func TestDownloadFilesEnforcesLimit(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	file := srv.AddFile(slackapi.File{ID: "F1", Name: "big.bin"}, make([]byte, 4096))
+	// Slack under-reports the size, so the limit must hold while streaming
+	messages := []model.Message{{Files: []model.File{{ID: file.ID, Name: file.Name, Size: 10, URLPrivate: file.URLPrivate}}}}
+
+	dir := t.TempDir()
+	DownloadFiles(srv.Client(), messages, dir, 1024)
+
+	if got := messages[0].Files[0].LocalPath; got != "" {
+		t.Errorf("local path = %q, want empty", got)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "files"))
+	if len(entries) != 0 {
+		t.Errorf("files left behind = %d, want 0", len(entries))
+	}
+}
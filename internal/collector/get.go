@@ -9,16 +9,25 @@ import (
 
 // GetOptions contains options for the get command
 type GetOptions struct {
-	URL        string
-	WithThread bool
+	URL          string
+	WithThread   bool
+	ResolveUsers bool
+	// DownloadDir enables file downloads into DownloadDir/files
+	DownloadDir string
+	MaxFileSize int64
+	RawBlocks   bool
 }
 
 // Get fetches a message or thread from a Slack URL
-func Get(client *slack.Client, opts GetOptions) (*model.Thread, error) {
+func Get(client slack.Service, opts GetOptions) (*model.Thread, error) {
 	urlInfo, err := slack.ParseURL(opts.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %w", err)
 	}
+	workspaceURL, err := slack.WorkspaceURL(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
 
 	// Determine the thread timestamp to use
 	threadTS := urlInfo.ThreadTS
@@ -26,44 +35,49 @@ func Get(client *slack.Client, opts GetOptions) (*model.Thread, error) {
 		threadTS = urlInfo.MessageTS
 	}
 
-	if opts.WithThread || urlInfo.ThreadTS != "" {
-		// Get the entire thread
-		return client.GetThread(urlInfo.ChannelID, threadTS)
-	}
-
-	// Get single message
-	messages, err := client.GetThreadReplies(urlInfo.ChannelID, urlInfo.MessageTS)
+	// GetThread tags the conversation type; only DMs and group DMs need
+	// another lookup for their participants
+	thread, err := client.GetThread(urlInfo.ChannelID, threadTS)
 	if err != nil {
+		if opts.WithThread || urlInfo.ThreadTS != "" {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to get message: %w", err)
 	}
-
-	// Filter to just the requested message
-	var targetMsg *model.Message
-	for _, msg := range messages {
-		if msg.ID == urlInfo.MessageTS {
-			targetMsg = &msg
-			break
-		}
+	listOpts := ListOptions{
+		ResolveUsers: opts.ResolveUsers,
+		IncludeDMs:   thread.ChannelType == model.ChannelTypeIM,
+		IncludeMPDMs: thread.ChannelType == model.ChannelTypeMPIM,
+		DownloadDir:  opts.DownloadDir,
+		MaxFileSize:  opts.MaxFileSize,
+		RawBlocks:    opts.RawBlocks,
 	}
 
-	if targetMsg == nil && len(messages) > 0 {
-		targetMsg = &messages[0]
+	if !opts.WithThread && urlInfo.ThreadTS == "" {
+		// Filter to just the requested message
+		var targetMsg *model.Message
+		for _, msg := range thread.Messages {
+			if msg.ID == urlInfo.MessageTS {
+				targetMsg = &msg
+				break
+			}
+		}
+		if targetMsg == nil && len(thread.Messages) > 0 {
+			targetMsg = &thread.Messages[0]
+		}
+		if targetMsg == nil {
+			return nil, fmt.Errorf("message not found")
+		}
+		thread.ThreadID = targetMsg.ThreadTS
+		thread.Messages = []model.Message{*targetMsg}
 	}
 
-	if targetMsg == nil {
-		return nil, fmt.Errorf("message not found")
+	thread.Messages = finishMessages(client, thread.Messages, listOpts)
+	thread.MessageCount = len(thread.Messages)
+	if len(thread.Messages) > 0 {
+		thread.Channel = thread.Messages[0].Channel
 	}
-
-	// Get channel name
-	channelName := client.GetChannelName(urlInfo.ChannelID)
-	targetMsg.Channel = channelName
-	targetMsg.ChannelID = urlInfo.ChannelID
-
-	return &model.Thread{
-		ThreadID:     targetMsg.ThreadTS,
-		Channel:      channelName,
-		ChannelID:    urlInfo.ChannelID,
-		Messages:     []model.Message{*targetMsg},
-		MessageCount: 1,
-	}, nil
+	threads := []model.Thread{*thread}
+	fillPermalinks(threads, workspaceURL)
+	return &threads[0], nil
 }
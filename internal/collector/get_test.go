@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/longkey1/slago/internal/slack/slacktest"
+)
+
+func TestGet(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddChannel("C1", "general")
+	srv.AddMessage("C1", newMessage("1736935200.000100", "1736935200.000100", "U1", "parent"))
+	srv.AddMessage("C1", newMessage("1736935260.000200", "1736935200.000100", "U2", "reply"))
+
+	tests := []struct {
+		name         string
+		url          string
+		withThread   bool
+		wantMessages int
+		wantID       string
+	}{
+		{
+			name:         "single message",
+			url:          "https://example.slack.com/archives/C1/p1736935200000100",
+			wantMessages: 1,
+			wantID:       "1736935200.000100",
+		},
+		{
+			name:         "thread flag",
+			url:          "https://example.slack.com/archives/C1/p1736935200000100",
+			withThread:   true,
+			wantMessages: 2,
+			wantID:       "1736935200.000100",
+		},
+		{
+			name:         "reply URL fetches thread",
+			url:          "https://example.slack.com/archives/C1/p1736935260000200?thread_ts=1736935200.000100",
+			wantMessages: 2,
+			wantID:       "1736935200.000100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thread, err := Get(srv.Client(), GetOptions{URL: tt.url, WithThread: tt.withThread})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got := len(thread.Messages); got != tt.wantMessages {
+				t.Errorf("Get() messages = %d, want %d", got, tt.wantMessages)
+			}
+			if thread.ThreadID != tt.wantID {
+				t.Errorf("Get() ThreadID = %q, want %q", thread.ThreadID, tt.wantID)
+			}
+			if thread.Channel != "general" {
+				t.Errorf("Get() Channel = %q, want %q", thread.Channel, "general")
+			}
+			if want := "https://example.slack.com/archives/C1/p1736935200000100"; thread.ThreadPermalink != want {
+				t.Errorf("Get() ThreadPermalink = %q, want %q", thread.ThreadPermalink, want)
+			}
+			if tt.wantMessages == 2 {
+				want := "https://example.slack.com/archives/C1/p1736935260000200?thread_ts=1736935200.000100"
+				if got := thread.Messages[1].Permalink; got != want {
+					t.Errorf("Get() reply Permalink = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+	if n := srv.Calls("chat.getPermalink"); n != 0 {
+		t.Errorf("chat.getPermalink called %d times, want 0", n)
+	}
+}
+
+func TestGetThreadLooksUpChannelOnce(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddChannel("C1", "general")
+	srv.AddMessage("C1", newMessage("1736935200.000100", "1736935200.000100", "U1", "parent"))
+
+	url := "https://example.slack.com/archives/C1/p1736935200000100"
+	thread, err := Get(srv.Client(), GetOptions{URL: url, WithThread: true})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if thread.ChannelType != "channel" {
+		t.Errorf("Get() ChannelType = %q, want %q", thread.ChannelType, "channel")
+	}
+	if n := srv.Calls("conversations.info"); n != 1 {
+		t.Errorf("conversations.info called %d times, want 1", n)
+	}
+}
+
+func TestGetRendersBlocks(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	msg := newMessage("1736935200.000100", "", "U1", "fallback")
+	if err := json.Unmarshal([]byte(`[{"type":"rich_text","elements":[{"type":"rich_text_section","elements":[{"type":"text","text":"done","style":{"bold":true}}]}]}]`), &msg.Blocks); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	srv.AddChannel("C1", "general")
+	srv.AddMessage("C1", msg)
+
+	url := "https://example.slack.com/archives/C1/p1736935200000100"
+	for _, raw := range []bool{false, true} {
+		thread, err := Get(srv.Client(), GetOptions{URL: url, RawBlocks: raw})
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		got := thread.Messages[0]
+		if got.ContentText != "fallback" {
+			t.Errorf("ContentText = %q, want %q", got.ContentText, "fallback")
+		}
+		if got.ContentMarkdown != "**done**" {
+			t.Errorf("ContentMarkdown = %q, want %q", got.ContentMarkdown, "**done**")
+		}
+		if hasBlocks := len(got.Blocks) > 0; hasBlocks != raw {
+			t.Errorf("RawBlocks=%v: blocks kept = %v", raw, hasBlocks)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package collector
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack"
+)
+
+// Collection sources for the list command
+const (
+	SourceSearch  = "search"
+	SourceHistory = "history"
+)
+
+// collectHistory walks conversations.history of the selected channels for the day
+func collectHistory(client slack.Service, opts ListOptions) ([]model.Message, error) {
+	channels, err := historyChannels(client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	oldest := opts.Date
+	latest := opts.Date.AddDate(0, 0, 1)
+	if opts.Location != nil {
+		oldest, latest = dayBounds(opts.Date, opts.Location)
+	}
+
+	var allMessages []model.Message
+	for _, ch := range channels {
+		messages, err := client.GetChannelHistory(ch.ID, oldest, latest)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to get history of #%s: %v\n", ch.Name, err)
+			continue
+		}
+
+		setChannel(messages, ch)
+
+		if opts.WithThread {
+			messages = expandReplies(client, messages)
+		}
+
+		allMessages = append(allMessages, filterMessages(messages, opts)...)
+	}
+
+	return allMessages, nil
+}
+
+// historyChannels checks that the filters work without search and returns
+// the channels to walk
+func historyChannels(client slack.Service, opts ListOptions) ([]model.Channel, error) {
+	if opts.IncludeDMs || opts.IncludeMPDMs {
+		return nil, fmt.Errorf("direct messages can only be collected with the search source")
+	}
+
+	if err := checkMentionIDs(opts.Mentions, "history mode"); err != nil {
+		return nil, err
+	}
+
+	allChannels, err := client.GetChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	return selectChannels(allChannels, opts.Channels, opts.ExcludeChannels)
+}
+
+// checkMentionIDs checks that every mention filter can be matched by ID,
+// which is all there is without search
+func checkMentionIDs(filters []slack.MentionFilter, mode string) error {
+	for _, filter := range filters {
+		if len(filter.IDs) == 0 {
+			return fmt.Errorf("mention %s cannot be matched in %s, use a user ID or user group", filter.Ref, mode)
+		}
+	}
+	return nil
+}
+
+// selectChannels picks channels by name or ID. Without an explicit selection
+// every channel the token is a member of is used.
+func selectChannels(channels []model.Channel, include, exclude []string) ([]model.Channel, error) {
+	excluded := make(map[string]bool)
+	for _, ref := range exclude {
+		excluded[strings.TrimPrefix(ref, "#")] = true
+	}
+
+	var selected []model.Channel
+	if len(include) == 0 {
+		for _, ch := range channels {
+			if ch.IsMember && !excluded[ch.Name] && !excluded[ch.ID] {
+				selected = append(selected, ch)
+			}
+		}
+		return selected, nil
+	}
+
+	for _, ref := range include {
+		ref = strings.TrimPrefix(ref, "#")
+		found := false
+		for _, ch := range channels {
+			if ch.Name == ref || ch.ID == ref {
+				if !excluded[ch.Name] && !excluded[ch.ID] {
+					selected = append(selected, ch)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("channel not found: %s", ref)
+		}
+	}
+	return selected, nil
+}
+
+// setChannel records the channel messages were read from
+func setChannel(messages []model.Message, ch model.Channel) {
+	for i := range messages {
+		messages[i].Channel = ch.Name
+		messages[i].ChannelID = ch.ID
+		messages[i].ChannelType = ch.Type()
+		messages[i].IsPrivate = ch.IsPrivate
+	}
+}
+
+// expandReplies replaces thread parents with their full threads
+func expandReplies(client slack.Service, messages []model.Message) []model.Message {
+	var result []model.Message
+	for _, msg := range messages {
+		if msg.ReplyCount == 0 {
+			result = append(result, msg)
+			continue
+		}
+
+		replies, err := client.GetThreadReplies(msg.ChannelID, msg.ID)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to get thread %s: %v\n", msg.ID, err)
+			result = append(result, msg)
+			continue
+		}
+
+		for i := range replies {
+			replies[i].Channel = msg.Channel
+			replies[i].ChannelID = msg.ChannelID
+			replies[i].ChannelType = msg.ChannelType
+			replies[i].IsPrivate = msg.IsPrivate
+		}
+		result = append(result, replies...)
+	}
+	return deduplicateMessages(result)
+}
+
+// filterMessages applies the author, mention and reaction filters locally,
+// which search does on the server. With WithThread whole threads with a match
+// are kept.
+func filterMessages(messages []model.Message, opts ListOptions) []model.Message {
+	if opts.Author == "" && len(opts.Mentions) == 0 && len(opts.Reactions) == 0 {
+		return messages
+	}
+
+	matchedThreads := make(map[string]bool)
+	var result []model.Message
+	for _, msg := range messages {
+		if matchesFilters(msg, opts) {
+			matchedThreads[messageKey(msg.ChannelID, threadKey(msg))] = true
+			result = append(result, msg)
+		}
+	}
+
+	if !opts.WithThread {
+		return result
+	}
+
+	result = nil
+	for _, msg := range messages {
+		if matchedThreads[messageKey(msg.ChannelID, threadKey(msg))] {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+func matchesFilters(msg model.Message, opts ListOptions) bool {
+	if opts.Author != "" && msg.Author != opts.Author {
+		return false
+	}
+
+	if !matchesMentions(msg, opts.Mentions) {
+		return false
+	}
+
+	for _, name := range opts.Reactions {
+		if !msg.HasReaction(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesMentions reports whether the message matches every mention filter
+func matchesMentions(msg model.Message, filters []slack.MentionFilter) bool {
+	for _, filter := range filters {
+		if !matchesMention(msg, filter) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesMention reports whether the message mentions any user or user
+// group of the filter
+func matchesMention(msg model.Message, filter slack.MentionFilter) bool {
+	for _, mention := range msg.Mentions {
+		if mention.Type != model.MentionTypeUser && mention.Type != model.MentionTypeUserGroup {
+			continue
+		}
+		if slices.Contains(filter.IDs, mention.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageKey identifies a message or thread across channels, where
+// timestamps may repeat
+func messageKey(channelID, ts string) string {
+	return channelID + "/" + ts
+}
+
+func threadKey(msg model.Message) string {
+	if msg.ThreadTS != "" {
+		return msg.ThreadTS
+	}
+	return msg.ID
+}
@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestListHistory(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddChannel("C1", "alerts")
+	random := slackapi.Channel{}
+	random.ID = "C2"
+	random.Name = "random"
+	srv.AddConversation(random)
+
+	parent := newMessage("1736935200.000100", "1736935200.000100", "U1", "parent")
+	parent.ReplyCount = 1
+	srv.AddMessage("C1", parent)
+	srv.AddMessage("C1", newMessage("1736935260.000200", "1736935200.000100", "U2", "reply"))
+	other := newMessage("1736938800.000300", "", "U2", "other author")
+	other.Reactions = []slackapi.ItemReaction{{Name: "white_check_mark", Count: 1, Users: []string{"U1"}}}
+	srv.AddMessage("C1", other)
+	srv.AddMessage("C1", newMessage("1736848800.000400", "", "U1", "previous day"))
+	srv.AddMessage("C2", newMessage("1736935200.000500", "", "U1", "not a member"))
+
+	tests := []struct {
+		name         string
+		opts         ListOptions
+		wantThreads  int
+		wantMessages int
+	}{
+		{
+			name:         "all members channels",
+			opts:         ListOptions{},
+			wantThreads:  2,
+			wantMessages: 2,
+		},
+		{
+			name:         "expand replies",
+			opts:         ListOptions{WithThread: true},
+			wantThreads:  2,
+			wantMessages: 3,
+		},
+		{
+			name:         "author filter keeps whole thread",
+			opts:         ListOptions{WithThread: true, Author: "U1"},
+			wantThreads:  1,
+			wantMessages: 2,
+		},
+		{
+			name:         "reaction filter",
+			opts:         ListOptions{Reactions: []string{"white_check_mark"}},
+			wantThreads:  1,
+			wantMessages: 1,
+		},
+		{
+			name:         "explicit channel",
+			opts:         ListOptions{Channels: []string{"random"}},
+			wantThreads:  1,
+			wantMessages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Date = time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+			opts.Source = SourceHistory
+
+			result, err := List(srv.Client(), opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := len(result.Threads); got != tt.wantThreads {
+				t.Errorf("List() threads = %d, want %d", got, tt.wantThreads)
+			}
+			if got := len(result.Messages); got != tt.wantMessages {
+				t.Errorf("List() messages = %d, want %d", got, tt.wantMessages)
+			}
+		})
+	}
+
+	if calls := srv.Calls("search.messages"); calls != 0 {
+		t.Errorf("search.messages calls = %d, want 0", calls)
+	}
+}
+
+func TestListExcludesBotsAndSubtypes(t *testing.T) {
+	for _, source := range []string{SourceHistory, SourceSearch} {
+		t.Run(source, func(t *testing.T) {
+			srv := slacktest.NewServer()
+			defer srv.Close()
+
+			srv.AddChannel("C1", "alerts")
+			srv.AddMessage("C1", newMessage("1736935200.000100", "", "U1", "human"))
+			alert := newMessage("1736935260.000200", "", "", "disk full")
+			alert.SubType = slackapi.MsgSubTypeBotMessage
+			alert.BotID = "B1"
+			alert.Username = "monitor"
+			srv.AddMessage("C1", alert)
+			join := newMessage("1736935320.000300", "", "U2", "<@U2> has joined the channel")
+			join.SubType = slackapi.MsgSubTypeChannelJoin
+			srv.AddMessage("C1", join)
+			// Search matches carry neither a subtype nor a bot ID
+			srv.AddSearchMatch(newSearchMatch("C1", "alerts", "1736935200.000100", "", "U1", "human"))
+			srv.AddSearchMatch(newSearchMatch("C1", "alerts", "1736935260.000200", "", "", "disk full"))
+			srv.AddSearchMatch(newSearchMatch("C1", "alerts", "1736935320.000300", "", "U2", "<@U2> has joined the channel"))
+
+			opts := ListOptions{
+				Date:   time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+				Source: source,
+			}
+			if source == SourceHistory {
+				result, err := List(srv.Client(), opts)
+				if err != nil {
+					t.Fatalf("List() error = %v", err)
+				}
+				for _, msg := range result.Messages {
+					if msg.ID == "1736935260.000200" && (msg.BotID != "B1" || msg.BotName != "monitor" || msg.Subtype != "bot_message") {
+						t.Errorf("bot message = %+v, want bot B1 named monitor", msg)
+					}
+				}
+			}
+
+			opts.ExcludeBots = true
+			opts.ExcludeSubtypes = []string{"channel_join", "channel_leave"}
+			result, err := List(srv.Client(), opts)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(result.Messages) != 1 || result.Messages[0].Content != "human" {
+				t.Errorf("List() messages = %+v, want only the human message", result.Messages)
+			}
+		})
+	}
+}
@@ -13,10 +13,39 @@ import (
 type ListOptions struct {
 	Date            time.Time
 	Author          string
-	Mentions        []string
+	Mentions        []slack.MentionFilter
 	Channels        []string
 	ExcludeChannels []string
 	WithThread      bool
+	ResolveUsers    bool
+	Source          string
+	IncludeDMs      bool
+	IncludeMPDMs    bool
+	Reactions       []string
+	WithReactions   bool
+	// WithFiles records the files of search matches outside threads, which
+	// search results leave out, at one call per match. DownloadDir implies
+	// it.
+	WithFiles bool
+	// DownloadDir enables file downloads into DownloadDir/files
+	DownloadDir     string
+	MaxFileSize     int64
+	RawBlocks       bool
+	ExcludeBots     bool
+	ExcludeSubtypes []string
+	// WorkspaceURL is the base of the permalinks built for every message
+	WorkspaceURL string
+	// Keywords, Has and Query add search terms (search source only)
+	Keywords []string
+	Has      []string
+	Query    string
+	// ShowQuery prints the search queries of the day
+	ShowQuery bool
+	// Location sets the day's boundaries to midnight in that zone. Without
+	// it search follows Slack's day boundaries and history uses UTC.
+	Location *time.Location
+	// LocalTime renders timestamps in Location instead of UTC
+	LocalTime bool
 }
 
 // DayResult contains the result of collecting messages for a day
@@ -28,50 +57,154 @@ type DayResult struct {
 }
 
 // List collects messages for a specific day
-func List(client *slack.Client, opts ListOptions) (*DayResult, error) {
+func List(client slack.Service, opts ListOptions) (*DayResult, error) {
+	var messages []model.Message
+	var err error
+	if opts.Source == SourceHistory {
+		messages, err = collectHistory(client, opts)
+	} else {
+		messages, err = collectSearch(client, opts)
+	}
+	if err != nil {
+		return &DayResult{
+			Date:  opts.Date,
+			Error: err,
+		}, err
+	}
+
+	messages = finishMessages(client, messages, opts)
+
+	// Group messages by thread
+	threads := groupByThread(messages)
+	fillPermalinks(threads, opts.WorkspaceURL)
+
+	return &DayResult{
+		Date:     opts.Date,
+		Threads:  threads,
+		Messages: messages,
+	}, nil
+}
+
+// finishMessages prepares collected messages for output: it drops excluded
+// messages, resolves users, renders content, tags conversations and
+// downloads files as opts ask
+func finishMessages(client slack.Service, messages []model.Message, opts ListOptions) []model.Message {
+	messages = excludeMessages(messages, opts.ExcludeBots, opts.ExcludeSubtypes)
+	stampCollected(messages)
+	if opts.ResolveUsers {
+		EnrichUsers(client, messages)
+	}
+	renderContent(client, messages, opts.ResolveUsers)
+	if !opts.RawBlocks {
+		dropBlocks(messages)
+	}
+	if opts.LocalTime && opts.Location != nil {
+		localizeTimes(messages, opts.Location)
+	}
+	if opts.IncludeDMs || opts.IncludeMPDMs {
+		describeConversations(client, messages)
+	}
+	if opts.DownloadDir != "" {
+		DownloadFiles(client, messages, opts.DownloadDir, opts.MaxFileSize)
+	}
+	return messages
+}
+
+// collectSearch collects the day's messages with search.messages
+func collectSearch(client slack.Service, opts ListOptions) ([]model.Message, error) {
 	// Calculate date range for search (day before and day after for accurate filtering)
 	prevDate := opts.Date.AddDate(0, 0, -1)
 	nextDate := opts.Date.AddDate(0, 0, 1)
 
-	searchOpts := slack.SearchOptions{
-		Author:          opts.Author,
-		Mentions:        opts.Mentions,
-		Channels:        opts.Channels,
-		ExcludeChannels: opts.ExcludeChannels,
-		After:           prevDate,
-		Before:          nextDate,
+	// Slack's search days may follow another zone, so search a day more on
+	// each side and keep the matches posted within the local day
+	var from, to time.Time
+	if opts.Location != nil {
+		from, to = dayBounds(opts.Date, opts.Location)
+		prevDate = prevDate.AddDate(0, 0, -1)
+		nextDate = nextDate.AddDate(0, 0, 1)
+	}
+
+	searchOpts := searchOptions(opts)
+	searchOpts.After = prevDate
+	searchOpts.Before = nextDate
+	searchOpts.From = from
+	searchOpts.To = to
+
+	if opts.ShowQuery {
+		for _, query := range slack.SearchQueries(searchOpts) {
+			fmt.Printf("[QUERY] %s: %s\n", opts.Date.Format("2006-01-02"), query)
+		}
 	}
 
 	messages, err := client.SearchMessages(searchOpts)
 	if err != nil {
-		return &DayResult{
-			Date:  opts.Date,
-			Error: err,
-		}, err
+		return nil, err
 	}
 
 	// If thread option is enabled, fetch full threads
 	if opts.WithThread {
 		messages, err = fetchThreads(client, messages)
 		if err != nil {
-			return &DayResult{
-				Date:  opts.Date,
-				Error: err,
-			}, err
+			return nil, err
 		}
 	}
 
-	// Group messages by thread
-	threads := groupByThread(messages)
+	return confirmMentions(messages, opts.Mentions), nil
+}
 
-	return &DayResult{
-		Date:     opts.Date,
-		Threads:  threads,
-		Messages: messages,
-	}, nil
+// searchOptions converts the list filters to search options without a date
+// range
+func searchOptions(opts ListOptions) slack.SearchOptions {
+	return slack.SearchOptions{
+		Author:          opts.Author,
+		Mentions:        opts.Mentions,
+		Channels:        opts.Channels,
+		ExcludeChannels: opts.ExcludeChannels,
+		IncludeDMs:      opts.IncludeDMs,
+		IncludeMPDMs:    opts.IncludeMPDMs,
+		Reactions:       opts.Reactions,
+		WithReactions:   opts.WithReactions,
+		WithFiles:       opts.WithFiles || opts.DownloadDir != "",
+		WithSubtypes:    opts.ExcludeBots || len(opts.ExcludeSubtypes) > 0,
+		Keywords:        opts.Keywords,
+		Has:             opts.Has,
+		Query:           opts.Query,
+	}
+}
+
+// confirmMentions keeps the threads in which a message mentions every
+// --mention filter by ID. Search also matches names, so its results can
+// include messages that do not mention the user. Filters without IDs are
+// left to search.
+func confirmMentions(messages []model.Message, filters []slack.MentionFilter) []model.Message {
+	var checked []slack.MentionFilter
+	for _, filter := range filters {
+		if len(filter.IDs) > 0 {
+			checked = append(checked, filter)
+		}
+	}
+	if len(checked) == 0 {
+		return messages
+	}
+
+	matchedThreads := make(map[string]bool)
+	for _, msg := range messages {
+		if matchesMentions(msg, checked) {
+			matchedThreads[messageKey(msg.ChannelID, threadKey(msg))] = true
+		}
+	}
+
+	var result []model.Message
+	for _, msg := range messages {
+		if matchedThreads[messageKey(msg.ChannelID, threadKey(msg))] {
+			result = append(result, msg)
+		}
+	}
+	return result
 }
 
-func fetchThreads(client *slack.Client, messages []model.Message) ([]model.Message, error) {
+func fetchThreads(client slack.Service, messages []model.Message) ([]model.Message, error) {
 	processedThreads := make(map[string]bool)
 	var allMessages []model.Message
 
@@ -81,7 +214,8 @@ func fetchThreads(client *slack.Client, messages []model.Message) ([]model.Messa
 			threadTS = msg.ID
 		}
 
-		if processedThreads[threadTS] {
+		key := messageKey(msg.ChannelID, threadTS)
+		if processedThreads[key] {
 			continue
 		}
 
@@ -101,10 +235,12 @@ func fetchThreads(client *slack.Client, messages []model.Message) ([]model.Messa
 			if threadMsgs[i].ChannelID == "" {
 				threadMsgs[i].ChannelID = msg.ChannelID
 			}
+			threadMsgs[i].ChannelType = msg.ChannelType
+			threadMsgs[i].IsPrivate = msg.IsPrivate
 		}
 
 		allMessages = append(allMessages, threadMsgs...)
-		processedThreads[threadTS] = true
+		processedThreads[key] = true
 	}
 
 	return deduplicateMessages(allMessages), nil
@@ -119,14 +255,17 @@ func groupByThread(messages []model.Message) []model.Thread {
 			threadTS = msg.ID
 		}
 
-		if thread, exists := threadMap[threadTS]; exists {
+		key := messageKey(msg.ChannelID, threadTS)
+		if thread, exists := threadMap[key]; exists {
 			thread.Messages = append(thread.Messages, msg)
 			thread.ThreadCount = len(thread.Messages)
 		} else {
-			threadMap[threadTS] = &model.Thread{
+			threadMap[key] = &model.Thread{
 				ThreadID:    threadTS,
 				Channel:     msg.Channel,
 				ChannelID:   msg.ChannelID,
+				ChannelType: msg.ChannelType,
+				IsPrivate:   msg.IsPrivate,
 				Messages:    []model.Message{msg},
 				ThreadCount: 1,
 			}
@@ -137,30 +276,66 @@ func groupByThread(messages []model.Message) []model.Thread {
 	var threads []model.Thread
 	for _, thread := range threadMap {
 		// Sort messages within thread by timestamp
-		sort.Slice(thread.Messages, func(i, j int) bool {
-			return thread.Messages[i].Timestamp.Before(thread.Messages[j].Timestamp)
+		sort.SliceStable(thread.Messages, func(i, j int) bool {
+			return model.CompareMessages(thread.Messages[i], thread.Messages[j]) < 0
 		})
 		threads = append(threads, *thread)
 	}
 
 	// Sort threads by first message timestamp
-	sort.Slice(threads, func(i, j int) bool {
-		if len(threads[i].Messages) == 0 || len(threads[j].Messages) == 0 {
-			return false
-		}
-		return threads[i].Messages[0].Timestamp.Before(threads[j].Messages[0].Timestamp)
+	sort.SliceStable(threads, func(i, j int) bool {
+		return model.CompareThreads(threads[i], threads[j]) < 0
 	})
 
 	return threads
 }
 
+// stampCollected records when messages were collected, so that merge can
+// tell which of two unedited copies is current
+func stampCollected(messages []model.Message) {
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := range messages {
+		messages[i].CollectedAt = &now
+	}
+}
+
+// dayBounds returns [00:00, 24:00) of a calendar date in a zone
+func dayBounds(date time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// localizeTimes renders the times of messages in a zone
+func localizeTimes(messages []model.Message, loc *time.Location) {
+	in := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		local := t.In(loc)
+		return &local
+	}
+	for i := range messages {
+		messages[i].Timestamp = messages[i].Timestamp.In(loc)
+		messages[i].EditedAt = in(messages[i].EditedAt)
+		messages[i].CollectedAt = in(messages[i].CollectedAt)
+	}
+}
+
+// dropBlocks removes the raw Block Kit blocks, which are only kept on request
+func dropBlocks(messages []model.Message) {
+	for i := range messages {
+		messages[i].Blocks = nil
+	}
+}
+
 func deduplicateMessages(messages []model.Message) []model.Message {
 	seen := make(map[string]bool)
 	var result []model.Message
 
 	for _, msg := range messages {
-		if !seen[msg.ID] {
-			seen[msg.ID] = true
+		key := messageKey(msg.ChannelID, msg.ID)
+		if !seen[key] {
+			seen[key] = true
 			result = append(result, msg)
 		}
 	}
@@ -0,0 +1,330 @@
+package collector
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func newMessage(ts, threadTS, user, text string) slackapi.Message {
+	msg := slackapi.Message{}
+	msg.Timestamp = ts
+	msg.ThreadTimestamp = threadTS
+	msg.User = user
+	msg.Text = text
+	return msg
+}
+
+func newSearchMatch(channelID, channelName, ts, threadTS, user, text string) slackapi.SearchMessage {
+	permalink := slacktest.Permalink(channelID, ts)
+	if threadTS != "" {
+		permalink += "?thread_ts=" + threadTS
+	}
+	return slackapi.SearchMessage{
+		Type:      "message",
+		Channel:   slackapi.CtxChannel{ID: channelID, Name: channelName},
+		User:      user,
+		Timestamp: ts,
+		Text:      text,
+		Permalink: permalink,
+	}
+}
+
+func TestListGroupsThreads(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddChannel("C1", "general")
+	srv.AddMessage("C1", newMessage("1736935200.000100", "1736935200.000100", "U1", "parent"))
+	srv.AddMessage("C1", newMessage("1736935260.000200", "1736935200.000100", "U2", "reply"))
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935260.000200", "1736935200.000100", "U2", "reply"))
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736938800.000300", "", "U1", "standalone"))
+
+	result, err := List(srv.Client(), ListOptions{
+		Date:   time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		Author: "U1",
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(result.Threads) != 2 {
+		t.Fatalf("List() threads = %d, want 2", len(result.Threads))
+	}
+	if got := result.Threads[0].ThreadID; got != "1736935200.000100" {
+		t.Errorf("first thread ID = %q, want %q", got, "1736935200.000100")
+	}
+	if got := len(result.Threads[0].Messages); got != 2 {
+		t.Errorf("first thread messages = %d, want 2", got)
+	}
+	if got := result.Threads[1].ThreadID; got != "1736938800.000300" {
+		t.Errorf("second thread ID = %q, want %q", got, "1736938800.000300")
+	}
+
+	queries := srv.Queries()
+	if len(queries) != 1 {
+		t.Fatalf("search queries = %d, want 1", len(queries))
+	}
+	for _, term := range []string{"from:<@U1>", "after:2025-01-14", "before:2025-01-16"} {
+		if !strings.Contains(queries[0], term) {
+			t.Errorf("query %q does not contain %q", queries[0], term)
+		}
+	}
+}
+
+func TestListPaginatesSearch(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	for i := 0; i < 150; i++ {
+		ts := fmt.Sprintf("%d.000000", 1736935200+i)
+		srv.AddSearchMatch(newSearchMatch("C1", "general", ts, "", "U1", "msg"))
+	}
+
+	result, err := List(srv.Client(), ListOptions{
+		Date: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if got := len(result.Messages); got != 150 {
+		t.Errorf("List() messages = %d, want 150", got)
+	}
+	if got := srv.Calls("search.messages"); got != 2 {
+		t.Errorf("search.messages calls = %d, want 2", got)
+	}
+}
+
+func TestListWithThread(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddMessage("C1", newMessage("1736935200.000100", "1736935200.000100", "U1", "parent"))
+	srv.AddMessage("C1", newMessage("1736935260.000200", "1736935200.000100", "U2", "reply 1"))
+	srv.AddMessage("C1", newMessage("1736935320.000300", "1736935200.000100", "U3", "reply 2"))
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935200.000100", "", "U1", "parent"))
+
+	result, err := List(srv.Client(), ListOptions{
+		Date:       time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		WithThread: true,
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(result.Threads) != 1 {
+		t.Fatalf("List() threads = %d, want 1", len(result.Threads))
+	}
+	thread := result.Threads[0]
+	if got := len(thread.Messages); got != 3 {
+		t.Errorf("thread messages = %d, want 3", got)
+	}
+	for _, msg := range thread.Messages {
+		if msg.Channel != "general" || msg.ChannelID != "C1" {
+			t.Errorf("message %s channel = %q/%q, want general/C1", msg.ID, msg.Channel, msg.ChannelID)
+		}
+	}
+}
+
+func TestGroupByThreadOrdersWithinSecond(t *testing.T) {
+	messages := []model.Message{
+		{ID: "1736935200.000300", ThreadTS: "1736935200.000100", ChannelID: "C1"},
+		{ID: "1736935200.000100", ThreadTS: "1736935200.000100", ChannelID: "C1"},
+		{ID: "1736935200.000200", ThreadTS: "1736935200.000100", ChannelID: "C1"},
+		{ID: "1736935200.000050", ThreadTS: "1736935200.000050", ChannelID: "C2"},
+	}
+	for i := range messages {
+		messages[i].Timestamp = messages[i].TS().Time()
+	}
+
+	threads := groupByThread(messages)
+	if len(threads) != 2 {
+		t.Fatalf("groupByThread() threads = %d, want 2", len(threads))
+	}
+	if threads[0].ThreadID != "1736935200.000050" {
+		t.Errorf("first thread = %q, want %q", threads[0].ThreadID, "1736935200.000050")
+	}
+
+	var got []string
+	for _, msg := range threads[1].Messages {
+		got = append(got, msg.ID)
+	}
+	want := []string{"1736935200.000100", "1736935200.000200", "1736935200.000300"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("thread messages = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByThreadKeepsChannelsApart(t *testing.T) {
+	messages := deduplicateMessages([]model.Message{
+		{ID: "1736935200.000100", ThreadTS: "1736935200.000100", ChannelID: "C1"},
+		{ID: "1736935200.000100", ThreadTS: "1736935200.000100", ChannelID: "C2"},
+		{ID: "1736935200.000100", ThreadTS: "1736935200.000100", ChannelID: "C1"},
+	})
+	if len(messages) != 2 {
+		t.Fatalf("deduplicateMessages() = %d messages, want 2", len(messages))
+	}
+	if threads := groupByThread(messages); len(threads) != 2 {
+		t.Errorf("groupByThread() threads = %d, want 2", len(threads))
+	}
+}
+
+func TestListReactions(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	done := []slackapi.ItemReaction{{Name: "white_check_mark", Count: 2, Users: []string{"U1", "U2"}}}
+	standalone := newMessage("1736938800.000300", "", "U1", "standalone")
+	standalone.Reactions = done
+	srv.AddMessage("C1", standalone)
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736938800.000300", "", "U1", "standalone"))
+
+	result, err := List(srv.Client(), ListOptions{
+		Date:      time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		Reactions: []string{":white_check_mark:"},
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(result.Messages) != 1 {
+		t.Fatalf("List() messages = %d, want 1", len(result.Messages))
+	}
+	reactions := result.Messages[0].Reactions
+	if len(reactions) != 1 || reactions[0].Name != "white_check_mark" || reactions[0].Count != 2 {
+		t.Errorf("reactions = %+v, want white_check_mark x2", reactions)
+	}
+	if got := srv.Calls("reactions.get"); got != 1 {
+		t.Errorf("reactions.get calls = %d, want 1", got)
+	}
+
+	queries := srv.Queries()
+	if len(queries) != 1 || !strings.Contains(queries[0], "has::white_check_mark:") {
+		t.Errorf("search queries = %v, want has::white_check_mark: term", queries)
+	}
+}
+
+func TestListConfirmsMentionsByID(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935200.000100", "", "U1", "ping <@U9>"))
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935260.000200", "", "U1", "ask @U9 by name"))
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935320.000300", "", "U1", "<!subteam^S1|@backend> please"))
+
+	tests := []struct {
+		name    string
+		filters []slack.MentionFilter
+		want    []string
+	}{
+		{
+			name:    "user ID",
+			filters: []slack.MentionFilter{{Ref: "U9", Terms: []string{"to:U9"}, IDs: []string{"U9"}}},
+			want:    []string{"1736935200.000100"},
+		},
+		{
+			name:    "user group and members",
+			filters: []slack.MentionFilter{{Ref: "@backend", Terms: []string{"<!subteam^S1>"}, IDs: []string{"S1", "U9"}}},
+			want:    []string{"1736935200.000100", "1736935320.000300"},
+		},
+		{
+			name:    "name only is left to search",
+			filters: []slack.MentionFilter{{Ref: "john", Terms: []string{"@john"}}},
+			want:    []string{"1736935200.000100", "1736935260.000200", "1736935320.000300"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := List(srv.Client(), ListOptions{
+				Date:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+				Mentions: tt.filters,
+			})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			var got []string
+			for _, msg := range result.Messages {
+				got = append(got, msg.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("List() messages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListSearchTerms(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	_, err := List(srv.Client(), ListOptions{
+		Date:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		Keywords: []string{"release notes"},
+		Has:      []string{"link"},
+		Query:    "is:thread",
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	queries := srv.Queries()
+	if len(queries) != 1 || !strings.HasPrefix(queries[0], `"release notes" has:link is:thread `) {
+		t.Errorf("search queries = %v, want keyword, has and query terms", queries)
+	}
+}
+
+func TestListLocalDay(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	ts := func(utc string) string {
+		t, _ := time.Parse(time.RFC3339, utc)
+		return fmt.Sprintf("%d.000100", t.Unix())
+	}
+	// The JST day 2025-01-15 is [2025-01-14T15:00Z, 2025-01-15T15:00Z)
+	inside := []string{ts("2025-01-14T16:00:00Z"), ts("2025-01-15T10:00:00Z")}
+	outside := []string{ts("2025-01-14T14:00:00Z"), ts("2025-01-15T16:00:00Z")}
+
+	for _, source := range []string{SourceSearch, SourceHistory} {
+		t.Run(source, func(t *testing.T) {
+			srv := slacktest.NewServer()
+			defer srv.Close()
+
+			srv.AddChannel("C1", "general")
+			for _, ts := range append(append([]string(nil), inside...), outside...) {
+				srv.AddMessage("C1", newMessage(ts, "", "U1", "hello"))
+				srv.AddSearchMatch(newSearchMatch("C1", "general", ts, "", "U1", "hello"))
+			}
+
+			result, err := List(srv.Client(), ListOptions{
+				Date:      time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+				Source:    source,
+				Location:  jst,
+				LocalTime: true,
+			})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+
+			var got []string
+			for _, msg := range result.Messages {
+				got = append(got, msg.ID)
+				if _, offset := msg.Timestamp.Zone(); offset != 9*60*60 {
+					t.Errorf("timestamp %v is not in JST", msg.Timestamp)
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, inside) {
+				t.Errorf("List() messages = %v, want %v", got, inside)
+			}
+		})
+	}
+}
@@ -9,6 +9,13 @@ import (
 // MergeOptions specifies options for merging threads
 type MergeOptions struct {
 	Threads []model.Thread
+	// KeepRevisions keeps the earlier texts of edited messages as revisions
+	KeepRevisions   bool
+	ExcludeBots     bool
+	ExcludeSubtypes []string
+	// WorkspaceURL is the base of the permalinks filled in for messages
+	// without one; by default it is taken from the input's permalinks
+	WorkspaceURL string
 }
 
 // MergeResult contains the merged threads and statistics
@@ -20,9 +27,12 @@ type MergeResult struct {
 	MergedMessageCount   int
 	DuplicateThreads     int
 	DuplicateMessages    int
+	ExcludedThreads      int
+	ExcludedMessages     int
 }
 
-// Merge merges multiple threads, deduplicating by ThreadID and Message ID
+// Merge merges multiple threads, deduplicating by channel and ThreadID and
+// by channel and Message ID
 func Merge(opts MergeOptions) *MergeResult {
 	result := &MergeResult{}
 
@@ -32,24 +42,41 @@ func Merge(opts MergeOptions) *MergeResult {
 		result.OriginalMessageCount += len(t.Messages)
 	}
 
-	// Merge threads by ThreadID
+	// Merge threads by channel and ThreadID
 	mergedThreads := mergeThreads(opts.Threads)
+	result.DuplicateThreads = result.OriginalThreadCount - len(mergedThreads)
+
+	// Drop excluded messages, and threads left without messages
+	if opts.ExcludeBots || len(opts.ExcludeSubtypes) > 0 {
+		kept := mergedThreads[:0]
+		for _, t := range mergedThreads {
+			before := len(t.Messages)
+			t.Messages = excludeMessages(t.Messages, opts.ExcludeBots, opts.ExcludeSubtypes)
+			result.ExcludedMessages += before - len(t.Messages)
+			if len(t.Messages) > 0 {
+				kept = append(kept, t)
+			} else {
+				result.ExcludedThreads++
+			}
+		}
+		mergedThreads = kept
+	}
 
 	// Deduplicate messages within each thread
 	for i := range mergedThreads {
-		mergedThreads[i].Messages = deduplicateMessagesKeepLatest(mergedThreads[i].Messages)
+		mergedThreads[i].Messages = deduplicateMessagesKeepLatest(mergedThreads[i].Messages, opts.KeepRevisions)
 		mergedThreads[i].MessageCount = len(mergedThreads[i].Messages)
 	}
 
+	workspaceURL := opts.WorkspaceURL
+	if workspaceURL == "" {
+		workspaceURL = workspaceURLOf(mergedThreads)
+	}
+	fillPermalinks(mergedThreads, workspaceURL)
+
 	// Sort threads by the first message's timestamp
-	sort.Slice(mergedThreads, func(i, j int) bool {
-		if len(mergedThreads[i].Messages) == 0 {
-			return true
-		}
-		if len(mergedThreads[j].Messages) == 0 {
-			return false
-		}
-		return mergedThreads[i].Messages[0].Timestamp.Before(mergedThreads[j].Messages[0].Timestamp)
+	sort.SliceStable(mergedThreads, func(i, j int) bool {
+		return model.CompareThreads(mergedThreads[i], mergedThreads[j]) < 0
 	})
 
 	// Count merged threads and messages
@@ -58,19 +85,20 @@ func Merge(opts MergeOptions) *MergeResult {
 		result.MergedMessageCount += len(t.Messages)
 	}
 
-	result.DuplicateThreads = result.OriginalThreadCount - result.MergedThreadCount
-	result.DuplicateMessages = result.OriginalMessageCount - result.MergedMessageCount
+	result.DuplicateMessages = result.OriginalMessageCount - result.ExcludedMessages - result.MergedMessageCount
 	result.Threads = mergedThreads
 
 	return result
 }
 
-// mergeThreads groups threads by ThreadID and merges their messages
+// mergeThreads groups threads by channel and ThreadID and merges their
+// messages
 func mergeThreads(threads []model.Thread) []model.Thread {
 	threadMap := make(map[string]*model.Thread)
 
 	for _, t := range threads {
-		if existing, ok := threadMap[t.ThreadID]; ok {
+		key := messageKey(t.ChannelID, t.ThreadID)
+		if existing, ok := threadMap[key]; ok {
 			// Merge messages
 			existing.Messages = append(existing.Messages, t.Messages...)
 			// Update counts
@@ -84,12 +112,14 @@ func mergeThreads(threads []model.Thread) []model.Thread {
 				ThreadPermalink: t.ThreadPermalink,
 				Channel:         t.Channel,
 				ChannelID:       t.ChannelID,
+				ChannelType:     t.ChannelType,
+				IsPrivate:       t.IsPrivate,
 				Messages:        make([]model.Message, len(t.Messages)),
 				MessageCount:    t.MessageCount,
 				ThreadCount:     t.ThreadCount,
 			}
 			copy(threadCopy.Messages, t.Messages)
-			threadMap[t.ThreadID] = &threadCopy
+			threadMap[key] = &threadCopy
 		}
 	}
 
@@ -102,31 +132,70 @@ func mergeThreads(threads []model.Thread) []model.Thread {
 	return result
 }
 
-// deduplicateMessagesKeepLatest deduplicates messages by ID, keeping the one with the latest timestamp
-func deduplicateMessagesKeepLatest(messages []model.Message) []model.Message {
+// deduplicateMessagesKeepLatest deduplicates messages by channel and ID,
+// keeping the most recently edited copy, or the most recently collected one
+// among equally edited copies. With keepRevisions the other texts become its
+// revisions.
+func deduplicateMessagesKeepLatest(messages []model.Message, keepRevisions bool) []model.Message {
 	messageMap := make(map[string]model.Message)
+	copies := make(map[string][]model.Message)
 
 	for _, m := range messages {
-		if existing, ok := messageMap[m.ID]; ok {
-			// Keep the one with the latest timestamp
-			if m.Timestamp.After(existing.Timestamp) {
-				messageMap[m.ID] = m
+		key := messageKey(m.ChannelID, m.ID)
+		if existing, ok := messageMap[key]; ok {
+			if m.IsNewerThan(existing) {
+				messageMap[key] = m
 			}
 		} else {
-			messageMap[m.ID] = m
+			messageMap[key] = m
 		}
+		copies[key] = append(copies[key], m)
 	}
 
 	// Convert map to slice
 	result := make([]model.Message, 0, len(messageMap))
-	for _, m := range messageMap {
+	for key, m := range messageMap {
+		if keepRevisions {
+			m.Revisions = collectRevisions(m, copies[key])
+		}
 		result = append(result, m)
 	}
 
 	// Sort by timestamp
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Timestamp.Before(result[j].Timestamp)
+	sort.SliceStable(result, func(i, j int) bool {
+		return model.CompareMessages(result[i], result[j]) < 0
 	})
 
 	return result
 }
+
+// collectRevisions gathers the texts of all copies of a message, including
+// revisions kept by earlier merges, that differ from the current text.
+// Revisions are ordered oldest first.
+func collectRevisions(current model.Message, copies []model.Message) []model.Revision {
+	var candidates []model.Revision
+	for _, c := range copies {
+		candidates = append(candidates, c.Revisions...)
+		candidates = append(candidates, model.Revision{
+			Content:  c.Content,
+			EditedAt: c.EditedAt,
+			EditedBy: c.EditedBy,
+		})
+	}
+
+	seen := make(map[string]bool)
+	var revisions []model.Revision
+	for _, r := range candidates {
+		if r.Content == current.Content || seen[r.Content] {
+			continue
+		}
+		seen[r.Content] = true
+		revisions = append(revisions, r)
+	}
+
+	sort.SliceStable(revisions, func(i, j int) bool {
+		a, b := revisions[i].EditedAt, revisions[j].EditedAt
+		return a == nil && b != nil || a != nil && b != nil && a.Before(*b)
+	})
+	return revisions
+}
@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+func TestMergePrefersNewestCopy(t *testing.T) {
+	at := func(hour int) *time.Time {
+		t := time.Date(2025, 1, 15, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	thread := func(msg model.Message) model.Thread {
+		return model.Thread{ThreadID: "1736935200.000100", ChannelID: "C1", Messages: []model.Message{msg}}
+	}
+	base := model.Message{ID: "1736935200.000100", ChannelID: "C1"}
+
+	original := base
+	original.Content = "draft"
+	original.CollectedAt = at(10)
+
+	recollected := base
+	recollected.Content = "draft"
+	recollected.CollectedAt = at(11)
+
+	edited := base
+	edited.Content = "final"
+	edited.EditedAt = at(12)
+	edited.EditedBy = "U1"
+	edited.CollectedAt = at(9)
+
+	// A search copy carries no edit time, but was collected after the edit
+	searched := base
+	searched.Content = "final, again"
+	searched.CollectedAt = at(13)
+
+	tests := []struct {
+		name          string
+		threads       []model.Thread
+		keepRevisions bool
+		wantContent   string
+		wantCollected *time.Time
+		wantRevisions []string
+	}{
+		{
+			name:          "later collection wins without edits",
+			threads:       []model.Thread{thread(recollected), thread(original)},
+			wantContent:   "draft",
+			wantCollected: at(11),
+		},
+		{
+			name:          "edit wins over later collection",
+			threads:       []model.Thread{thread(edited), thread(recollected)},
+			wantContent:   "final",
+			wantCollected: at(9),
+		},
+		{
+			name:          "search copy collected after the edit wins",
+			threads:       []model.Thread{thread(edited), thread(searched)},
+			wantContent:   "final, again",
+			wantCollected: at(13),
+		},
+		{
+			name:          "revisions keep earlier texts",
+			threads:       []model.Thread{thread(original), thread(edited), thread(recollected)},
+			keepRevisions: true,
+			wantContent:   "final",
+			wantCollected: at(9),
+			wantRevisions: []string{"draft"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Merge(MergeOptions{Threads: tt.threads, KeepRevisions: tt.keepRevisions})
+			if len(result.Threads) != 1 || len(result.Threads[0].Messages) != 1 {
+				t.Fatalf("Merge() = %+v, want one thread with one message", result.Threads)
+			}
+
+			got := result.Threads[0].Messages[0]
+			if got.Content != tt.wantContent {
+				t.Errorf("content = %q, want %q", got.Content, tt.wantContent)
+			}
+			if !got.CollectedAt.Equal(*tt.wantCollected) {
+				t.Errorf("collected_at = %v, want %v", got.CollectedAt, tt.wantCollected)
+			}
+
+			var revisions []string
+			for _, r := range got.Revisions {
+				revisions = append(revisions, r.Content)
+			}
+			if len(revisions) != len(tt.wantRevisions) {
+				t.Fatalf("revisions = %v, want %v", revisions, tt.wantRevisions)
+			}
+			for i := range revisions {
+				if revisions[i] != tt.wantRevisions[i] {
+					t.Errorf("revisions = %v, want %v", revisions, tt.wantRevisions)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeKeepsChannelsApart(t *testing.T) {
+	threads := []model.Thread{
+		{ThreadID: "1.000001", ChannelID: "C1", Messages: []model.Message{{ID: "1.000001", ChannelID: "C1", Content: "one"}}},
+		{ThreadID: "1.000001", ChannelID: "C2", Messages: []model.Message{{ID: "1.000001", ChannelID: "C2", Content: "two"}}},
+	}
+
+	result := Merge(MergeOptions{Threads: threads})
+	if result.MergedThreadCount != 2 || result.MergedMessageCount != 2 {
+		t.Errorf("Merge() = %d threads / %d messages, want 2 / 2", result.MergedThreadCount, result.MergedMessageCount)
+	}
+	if result.DuplicateThreads != 0 || result.DuplicateMessages != 0 {
+		t.Errorf("Merge() duplicates = %d / %d, want 0 / 0", result.DuplicateThreads, result.DuplicateMessages)
+	}
+}
+
+func TestMergeExcludes(t *testing.T) {
+	threads := []model.Thread{
+		{ThreadID: "1.000001", ChannelID: "C1", Messages: []model.Message{
+			{ID: "1.000001", ChannelID: "C1", Author: "U1"},
+			{ID: "1.000002", ChannelID: "C1", BotID: "B1"},
+		}},
+		{ThreadID: "2.000001", ChannelID: "C1", Messages: []model.Message{
+			{ID: "2.000001", ChannelID: "C1", Author: "U2", Subtype: "channel_join"},
+		}},
+	}
+
+	result := Merge(MergeOptions{Threads: threads, ExcludeBots: true, ExcludeSubtypes: []string{"channel_join"}})
+	if result.MergedThreadCount != 1 || result.MergedMessageCount != 1 {
+		t.Errorf("Merge() = %d threads / %d messages, want 1 / 1", result.MergedThreadCount, result.MergedMessageCount)
+	}
+	if result.ExcludedMessages != 2 || result.ExcludedThreads != 1 {
+		t.Errorf("Merge() excluded = %d messages / %d threads, want 2 / 1", result.ExcludedMessages, result.ExcludedThreads)
+	}
+	if result.DuplicateMessages != 0 || result.DuplicateThreads != 0 {
+		t.Errorf("Merge() duplicates = %d / %d, want 0 / 0", result.DuplicateMessages, result.DuplicateThreads)
+	}
+}
+
+func TestMergeFillsPermalinks(t *testing.T) {
+	threads := []model.Thread{
+		{ThreadID: "1.000001", ChannelID: "C1", Messages: []model.Message{
+			{ID: "1.000001", ChannelID: "C1", ThreadTS: "1.000001", Permalink: "https://example.slack.com/archives/C1/p1000001"},
+		}},
+		{ThreadID: "1.000001", ChannelID: "C1", Messages: []model.Message{
+			{ID: "1.000002", ChannelID: "C1", ThreadTS: "1.000001"},
+		}},
+	}
+
+	tests := []struct {
+		name         string
+		workspaceURL string
+		wantReply    string
+	}{
+		{
+			name:      "from input permalinks",
+			wantReply: "https://example.slack.com/archives/C1/p1000002?thread_ts=1.000001",
+		},
+		{
+			name:         "explicit workspace URL",
+			workspaceURL: "https://other.slack.com",
+			wantReply:    "https://other.slack.com/archives/C1/p1000002?thread_ts=1.000001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Merge(MergeOptions{Threads: threads, WorkspaceURL: tt.workspaceURL})
+			if len(result.Threads) != 1 || len(result.Threads[0].Messages) != 2 {
+				t.Fatalf("Merge() = %+v, want one thread with two messages", result.Threads)
+			}
+			got := result.Threads[0]
+			if got.Messages[0].Permalink != "https://example.slack.com/archives/C1/p1000001" {
+				t.Errorf("parent permalink = %q, want it kept", got.Messages[0].Permalink)
+			}
+			if got.Messages[1].Permalink != tt.wantReply {
+				t.Errorf("reply permalink = %q, want %q", got.Messages[1].Permalink, tt.wantReply)
+			}
+			if got.ThreadPermalink == "" {
+				t.Error("thread permalink is empty")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack"
+)
+
+// fillPermalinks builds the permalinks of threads and their messages from the
+// workspace URL, keeping any that are already set
+func fillPermalinks(threads []model.Thread, workspaceURL string) {
+	if workspaceURL == "" {
+		return
+	}
+	for i := range threads {
+		t := &threads[i]
+		if t.ThreadPermalink == "" && t.ChannelID != "" {
+			t.ThreadPermalink = slack.Permalink(workspaceURL, t.ChannelID, t.ThreadID, "")
+		}
+		for j := range t.Messages {
+			msg := &t.Messages[j]
+			if msg.Permalink == "" && msg.ChannelID != "" {
+				msg.Permalink = slack.Permalink(workspaceURL, msg.ChannelID, msg.ID, msg.ThreadTS)
+			}
+		}
+	}
+}
+
+// workspaceURLOf finds the workspace URL in the permalinks of collected
+// threads, for files written without one at hand
+func workspaceURLOf(threads []model.Thread) string {
+	for _, t := range threads {
+		links := []string{t.ThreadPermalink}
+		for _, msg := range t.Messages {
+			links = append(links, msg.Permalink)
+		}
+		for _, link := range links {
+			if link == "" {
+				continue
+			}
+			if base, err := slack.WorkspaceURL(link); err == nil {
+				return base
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,335 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack"
+)
+
+// searchOverlap is how far before its mark a search is repeated. Search
+// indexes messages with a delay, so the newest matches may be missing from
+// a run.
+const searchOverlap = 10 * time.Minute
+
+// SyncState is what sync remembers between runs: a high-water mark per
+// search query and per channel
+type SyncState struct {
+	Queries  map[string]*SyncMark `json:"queries,omitempty"`
+	Channels map[string]*SyncMark `json:"channels,omitempty"`
+}
+
+// SyncMark records the newest message seen by a query or in a channel
+type SyncMark struct {
+	LastTS  string    `json:"last_ts,omitempty"`
+	LastRun time.Time `json:"last_run"`
+	// Threads maps watched thread parents to their latest reply
+	Threads map[string]string `json:"threads,omitempty"`
+}
+
+// LoadSyncState reads the sync state. A missing file is an empty state.
+func LoadSyncState(path string) (*SyncState, error) {
+	state := &SyncState{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid sync state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the sync state
+func (s *SyncState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return nil
+}
+
+func (s *SyncState) query(key string) *SyncMark {
+	if s.Queries == nil {
+		s.Queries = make(map[string]*SyncMark)
+	}
+	if s.Queries[key] == nil {
+		s.Queries[key] = &SyncMark{}
+	}
+	return s.Queries[key]
+}
+
+func (s *SyncState) channel(id string) *SyncMark {
+	if s.Channels == nil {
+		s.Channels = make(map[string]*SyncMark)
+	}
+	if s.Channels[id] == nil {
+		s.Channels[id] = &SyncMark{}
+	}
+	return s.Channels[id]
+}
+
+// since returns where a run starts: the mark, or start on the first run
+func (m *SyncMark) since(start time.Time) time.Time {
+	if m.LastTS == "" {
+		return start
+	}
+	return model.TS(m.LastTS).Time()
+}
+
+// isNew reports whether a message is newer than the mark, or on the first
+// run not older than start
+func (m *SyncMark) isNew(msg model.Message, start time.Time) bool {
+	if m.LastTS == "" {
+		return !msg.TS().Time().Before(start)
+	}
+	return msg.TS().After(model.TS(m.LastTS))
+}
+
+// replied reports whether a thread got replies since it was last seen.
+// Threads not seen before count when their latest reply is past the mark.
+func (m *SyncMark) replied(parent model.Message, start time.Time) bool {
+	if latest, ok := m.Threads[parent.ID]; ok {
+		return latest != parent.LatestReply
+	}
+	return model.TS(parent.LatestReply).Time().After(m.since(start))
+}
+
+// advance moves the mark to the newest message of a run
+func (m *SyncMark) advance(messages []model.Message, now time.Time) {
+	for _, msg := range messages {
+		if m.LastTS == "" || msg.TS().After(model.TS(m.LastTS)) {
+			m.LastTS = msg.ID
+		}
+	}
+	m.LastRun = now
+}
+
+// SyncOptions contains options for the sync command
+type SyncOptions struct {
+	// List holds the filters and output options; Date is not used
+	List  ListOptions
+	State *SyncState
+	// Since is where the first run starts
+	Since time.Time
+	// ThreadLookback is how long threads are watched for new replies
+	// (WithThread only)
+	ThreadLookback time.Duration
+	Now            time.Time
+}
+
+// SyncResult contains the messages fetched by a sync run
+type SyncResult struct {
+	Threads          []model.Thread
+	Messages         []model.Message
+	NewMessages      int
+	RefetchedThreads int
+}
+
+// Sync fetches the messages posted since the last run, and with WithThread
+// the threads that got new replies, advancing the marks in State
+func Sync(client slack.Service, opts SyncOptions) (*SyncResult, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	result := &SyncResult{}
+	var messages []model.Message
+	var err error
+	if opts.List.Source == SourceHistory {
+		messages, err = syncHistory(client, opts, result)
+	} else {
+		messages, err = syncSearch(client, opts, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages = finishMessages(client, messages, opts.List)
+
+	result.Messages = messages
+	result.Threads = groupByThread(messages)
+	fillPermalinks(result.Threads, opts.List.WorkspaceURL)
+	return result, nil
+}
+
+// syncSearch searches from the query's mark to now
+func syncSearch(client slack.Service, opts SyncOptions, result *SyncResult) ([]model.Message, error) {
+	searchOpts := searchOptions(opts.List)
+	key := strings.Join(slack.SearchQueries(searchOpts), " | ")
+	mark := opts.State.query(key)
+
+	// Search dates may follow another zone, so they start a day early and
+	// the matches are cut at the exact time
+	from := mark.since(opts.Since)
+	if mark.LastTS != "" {
+		from = from.Add(-searchOverlap)
+	}
+	searchOpts.After = from.UTC().Truncate(24*time.Hour).AddDate(0, 0, -2)
+	searchOpts.From = from
+	searchOpts.To = opts.Now
+
+	if opts.List.ShowQuery {
+		for _, query := range slack.SearchQueries(searchOpts) {
+			fmt.Printf("[QUERY] %s\n", query)
+		}
+	}
+
+	messages, err := client.SearchMessages(searchOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.List.WithThread {
+		messages, err = fetchThreads(client, messages)
+		if err != nil {
+			return nil, err
+		}
+	}
+	messages = confirmMentions(messages, opts.List.Mentions)
+
+	for _, msg := range messages {
+		if mark.isNew(msg, opts.Since) {
+			result.NewMessages++
+		} else if latest, ok := mark.Threads[messageKey(msg.ChannelID, msg.ID)]; ok && msg.IsThreadParent && latest != msg.LatestReply {
+			result.RefetchedThreads++
+		}
+	}
+	mark.advance(messages, opts.Now)
+
+	if opts.List.WithThread {
+		messages = append(messages, recheckThreads(client, mark, messages, opts, result)...)
+	}
+	return messages, nil
+}
+
+// recheckThreads fetches the threads kept by earlier runs that the search
+// did not find again, and returns those whose latest reply changed. Replies
+// that do not match the query are only found this way. Mark.Threads is
+// reset to the threads started within the lookback window, keyed by
+// channel and parent ts.
+func recheckThreads(client slack.Service, mark *SyncMark, found []model.Message, opts SyncOptions, result *SyncResult) []model.Message {
+	watchFrom := opts.Now.Add(-opts.ThreadLookback)
+	threads := make(map[string]string)
+	for _, msg := range found {
+		if msg.IsThreadParent && msg.ReplyCount > 0 && !msg.TS().Time().Before(watchFrom) {
+			threads[messageKey(msg.ChannelID, msg.ID)] = msg.LatestReply
+		}
+	}
+
+	names := make(map[string]string)
+	var refetched []model.Message
+	for key, latest := range mark.Threads {
+		channelID, threadTS, ok := strings.Cut(key, "/")
+		if !ok || model.TS(threadTS).Time().Before(watchFrom) {
+			continue
+		}
+		if _, ok := threads[key]; ok {
+			continue
+		}
+
+		replies, err := client.GetThreadReplies(channelID, threadTS)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to get thread %s: %v\n", threadTS, err)
+			threads[key] = latest
+			continue
+		}
+		if len(replies) == 0 {
+			continue
+		}
+		threads[key] = replies[0].LatestReply
+		if replies[0].LatestReply == latest {
+			continue
+		}
+
+		name, ok := names[channelID]
+		if !ok {
+			name = channelID
+			if ch, err := client.GetChannel(channelID); err == nil {
+				name = ch.Name
+			}
+			names[channelID] = name
+		}
+		for i := range replies {
+			replies[i].Channel = name
+		}
+		refetched = append(refetched, replies...)
+		result.RefetchedThreads++
+	}
+
+	mark.Threads = threads
+	return refetched
+}
+
+// syncHistory reads each channel's history from its mark. With WithThread
+// the history of the lookback window is read as well, and threads whose
+// latest reply changed are fetched again.
+func syncHistory(client slack.Service, opts SyncOptions, result *SyncResult) ([]model.Message, error) {
+	channels, err := historyChannels(client, opts.List)
+	if err != nil {
+		return nil, err
+	}
+
+	var allMessages []model.Message
+	for _, ch := range channels {
+		mark := opts.State.channel(ch.ID)
+
+		oldest := mark.since(opts.Since)
+		watchFrom := opts.Now.Add(-opts.ThreadLookback)
+		if opts.List.WithThread && watchFrom.Before(oldest) {
+			oldest = watchFrom
+		}
+
+		messages, err := client.GetChannelHistory(ch.ID, oldest, opts.Now)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to get history of #%s: %v\n", ch.Name, err)
+			continue
+		}
+		setChannel(messages, ch)
+
+		threads := make(map[string]string)
+		var picked []model.Message
+		for _, msg := range messages {
+			isNew := mark.isNew(msg, opts.Since)
+			if isNew {
+				result.NewMessages++
+			}
+			changed := false
+			if opts.List.WithThread && msg.ReplyCount > 0 {
+				threads[msg.ID] = msg.LatestReply
+				changed = !isNew && mark.replied(msg, opts.Since)
+				if changed {
+					result.RefetchedThreads++
+				}
+			}
+			if isNew || changed {
+				picked = append(picked, msg)
+			}
+		}
+
+		if opts.List.WithThread {
+			picked = expandReplies(client, picked)
+			mark.Threads = threads
+		}
+		mark.advance(messages, opts.Now)
+
+		allMessages = append(allMessages, filterMessages(picked, opts.List)...)
+	}
+
+	return allMessages, nil
+}
@@ -0,0 +1,173 @@
+package collector
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/slack/slacktest"
+)
+
+func TestSyncHistory(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddChannel("C1", "alerts")
+	srv.AddMessage("C1", newMessage("1736935200.000100", "1736935200.000100", "U1", "parent"))
+	srv.AddMessage("C1", newMessage("1736935260.000200", "1736935200.000100", "U2", "reply"))
+	srv.AddMessage("C1", newMessage("1736935300.000300", "1736935300.000300", "U1", "quiet thread"))
+	srv.AddMessage("C1", newMessage("1736935310.000400", "1736935300.000300", "U2", "quiet reply"))
+	srv.AddMessage("C1", newMessage("1736848800.000500", "", "U1", "before since"))
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	start := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	run := func(now time.Time) *SyncResult {
+		t.Helper()
+		state, err := LoadSyncState(path)
+		if err != nil {
+			t.Fatalf("LoadSyncState() error = %v", err)
+		}
+		result, err := Sync(srv.Client(), SyncOptions{
+			List:           ListOptions{Source: SourceHistory, WithThread: true},
+			State:          state,
+			Since:          start,
+			ThreadLookback: 7 * 24 * time.Hour,
+			Now:            now,
+		})
+		if err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if err := state.Save(path); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		return result
+	}
+
+	first := run(time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC))
+	if first.NewMessages != 2 || len(first.Messages) != 4 {
+		t.Fatalf("first run: new = %d, messages = %d, want 2 and 4", first.NewMessages, len(first.Messages))
+	}
+
+	srv.AddMessage("C1", newMessage("1736946000.000600", "1736935200.000100", "U3", "late reply"))
+	srv.AddMessage("C1", newMessage("1736946060.000700", "", "U1", "new message"))
+	replies := srv.Calls("conversations.replies")
+
+	second := run(time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC))
+	if second.NewMessages != 1 || second.RefetchedThreads != 1 {
+		t.Errorf("second run: new = %d, refetched = %d, want 1 and 1", second.NewMessages, second.RefetchedThreads)
+	}
+	if calls := srv.Calls("conversations.replies") - replies; calls != 1 {
+		t.Errorf("second run conversations.replies calls = %d, want 1", calls)
+	}
+
+	var ids []string
+	for _, msg := range second.Messages {
+		ids = append(ids, msg.ID)
+	}
+	slices.Sort(ids)
+	want := []string{"1736935200.000100", "1736935260.000200", "1736946000.000600", "1736946060.000700"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("second run messages = %v, want %v", ids, want)
+	}
+
+	third := run(time.Date(2025, 1, 15, 15, 0, 0, 0, time.UTC))
+	if len(third.Messages) != 0 {
+		t.Errorf("third run messages = %d, want 0", len(third.Messages))
+	}
+}
+
+func TestSyncSearch(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935200.000100", "", "U1", "first"))
+
+	state := &SyncState{}
+	opts := SyncOptions{
+		List:  ListOptions{Author: "U1"},
+		State: state,
+		Since: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		Now:   time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	first, err := Sync(srv.Client(), opts)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if first.NewMessages != 1 {
+		t.Errorf("first run new = %d, want 1", first.NewMessages)
+	}
+	mark := state.Queries["from:<@U1> -is:dm -is:mpdm"]
+	if mark == nil || mark.LastTS != "1736935200.000100" {
+		t.Fatalf("query mark = %+v, want last_ts 1736935200.000100", mark)
+	}
+
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736946000.000200", "", "U1", "second"))
+	opts.Now = time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC)
+	second, err := Sync(srv.Client(), opts)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if second.NewMessages != 1 || mark.LastTS != "1736946000.000200" {
+		t.Errorf("second run new = %d, last_ts = %s, want 1 and 1736946000.000200", second.NewMessages, mark.LastTS)
+	}
+
+	queries := srv.Queries()
+	if last := queries[len(queries)-1]; !strings.Contains(last, "after:2025-01-13") {
+		t.Errorf("second run query = %q, want it to start from the mark", last)
+	}
+}
+
+func TestSyncSearchRechecksThreads(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddChannel("C1", "general")
+	srv.AddMessage("C1", newMessage("1736935200.000100", "1736935200.000100", "U1", "parent"))
+	srv.AddMessage("C1", newMessage("1736938800.000200", "1736935200.000100", "U2", "reply"))
+	srv.AddSearchMatch(newSearchMatch("C1", "general", "1736935200.000100", "", "U1", "parent"))
+
+	state := &SyncState{}
+	opts := SyncOptions{
+		List:           ListOptions{Author: "U1", WithThread: true},
+		State:          state,
+		Since:          time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		ThreadLookback: 7 * 24 * time.Hour,
+		Now:            time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	if _, err := Sync(srv.Client(), opts); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	mark := state.Queries["from:<@U1> -is:dm -is:mpdm"]
+	if got := mark.Threads["C1/1736935200.000100"]; got != "1736938800.000200" {
+		t.Fatalf("tracked latest reply = %q, want 1736938800.000200", got)
+	}
+
+	// The reply is not by the author, so only the recheck finds it
+	srv.AddMessage("C1", newMessage("1736946000.000300", "1736935200.000100", "U3", "late reply"))
+	opts.Now = time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC)
+	second, err := Sync(srv.Client(), opts)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if second.RefetchedThreads != 1 || len(second.Messages) != 3 {
+		t.Errorf("second run: refetched = %d, messages = %d, want 1 and 3", second.RefetchedThreads, len(second.Messages))
+	}
+	if got := second.Messages[0].Channel; got != "general" {
+		t.Errorf("refetched Channel = %q, want general", got)
+	}
+	if got := mark.Threads["C1/1736935200.000100"]; got != "1736946000.000300" {
+		t.Errorf("tracked latest reply = %q, want 1736946000.000300", got)
+	}
+
+	third, err := Sync(srv.Client(), opts)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(third.Messages) != 0 {
+		t.Errorf("third run messages = %d, want 0", len(third.Messages))
+	}
+}
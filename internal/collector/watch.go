@@ -0,0 +1,162 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack"
+)
+
+// WatchOptions contains options for the watch command
+type WatchOptions struct {
+	// List holds the filters and output options; Date and Source are not used
+	List ListOptions
+	// AppToken is the app-level token (xapp-) Socket Mode connects with
+	AppToken string
+}
+
+// Watch receives messages over Socket Mode as they are posted or edited and
+// calls handle with those matching the filters, grouped by thread, until
+// ctx is done. With WithThread the first match in a thread brings the whole
+// thread, and later messages in it are kept.
+func Watch(ctx context.Context, client slack.Service, opts WatchOptions, handle func([]model.Thread)) error {
+	if opts.AppToken == "" {
+		return fmt.Errorf("an app-level token (xapp-) is required for Socket Mode")
+	}
+	if err := checkMentionIDs(opts.List.Mentions, "watch mode"); err != nil {
+		return err
+	}
+
+	w := &watcher{
+		client:   client,
+		opts:     opts.List,
+		exclude:  make(map[string]bool),
+		threads:  make(map[string]bool),
+		channels: make(map[string]*model.Channel),
+	}
+	for _, ref := range opts.List.ExcludeChannels {
+		w.exclude[strings.TrimPrefix(ref, "#")] = true
+	}
+	if len(opts.List.Channels) > 0 {
+		allChannels, err := client.GetChannels()
+		if err != nil {
+			return err
+		}
+		selected, err := selectChannels(allChannels, opts.List.Channels, opts.List.ExcludeChannels)
+		if err != nil {
+			return err
+		}
+		w.include = make(map[string]bool)
+		for _, ch := range selected {
+			w.include[ch.ID] = true
+		}
+	}
+
+	return client.Listen(ctx, opts.AppToken, func(msg model.Message) {
+		messages := w.keep(msg)
+		if len(messages) == 0 {
+			return
+		}
+		handle(w.finish(messages))
+	})
+}
+
+// watcher applies the list filters to a stream of messages
+type watcher struct {
+	client slack.Service
+	opts   ListOptions
+	// include holds the IDs of the channels to watch, nil for every channel
+	include map[string]bool
+	exclude map[string]bool
+	// threads holds the threads already kept, by channel and thread ts
+	threads map[string]bool
+	// channels caches the channels of received messages by ID
+	channels map[string]*model.Channel
+}
+
+// channel looks up a channel once, falling back to its ID as the name
+func (w *watcher) channel(channelID string) *model.Channel {
+	if ch, ok := w.channels[channelID]; ok {
+		return ch
+	}
+	ch, err := w.client.GetChannel(channelID)
+	if err != nil {
+		fmt.Printf("[WARN] Could not get channel info for %s: %v\n", channelID, err)
+		ch = &model.Channel{ID: channelID, Name: channelID}
+	}
+	w.channels[channelID] = ch
+	return ch
+}
+
+// keep returns the messages to write for a received message: none, the
+// message, or with WithThread its whole thread
+func (w *watcher) keep(msg model.Message) []model.Message {
+	ch := w.channel(msg.ChannelID)
+	if !w.watches(*ch) {
+		return nil
+	}
+	messages := []model.Message{msg}
+	setChannel(messages, *ch)
+
+	messages = excludeMessages(messages, w.opts.ExcludeBots, w.opts.ExcludeSubtypes)
+	if len(messages) == 0 {
+		return nil
+	}
+	msg = messages[0]
+
+	if !w.opts.WithThread {
+		if !matchesFilters(msg, w.opts) {
+			return nil
+		}
+		return messages
+	}
+
+	key := messageKey(msg.ChannelID, threadKey(msg))
+	if w.threads[key] {
+		return messages
+	}
+	if !matchesFilters(msg, w.opts) {
+		return nil
+	}
+	w.threads[key] = true
+
+	// A new parent has no replies yet
+	if msg.IsThreadParent && msg.ReplyCount == 0 {
+		return messages
+	}
+	thread, err := w.client.GetThreadReplies(msg.ChannelID, threadKey(msg))
+	if err != nil {
+		fmt.Printf("[WARN] Failed to get thread %s: %v\n", threadKey(msg), err)
+		return messages
+	}
+	setChannel(thread, *ch)
+	return excludeMessages(thread, w.opts.ExcludeBots, w.opts.ExcludeSubtypes)
+}
+
+// watches reports whether messages of a channel are collected
+func (w *watcher) watches(ch model.Channel) bool {
+	switch ch.Type() {
+	case model.ChannelTypeIM:
+		if !w.opts.IncludeDMs {
+			return false
+		}
+	case model.ChannelTypeMPIM:
+		if !w.opts.IncludeMPDMs {
+			return false
+		}
+	}
+	if w.include != nil {
+		return w.include[ch.ID]
+	}
+	return !w.exclude[ch.Name] && !w.exclude[ch.ID]
+}
+
+// finish prepares kept messages like list does and groups them by thread
+func (w *watcher) finish(messages []model.Message) []model.Thread {
+	messages = finishMessages(w.client, messages, w.opts)
+	threads := groupByThread(messages)
+	fillPermalinks(threads, w.opts.WorkspaceURL)
+	return threads
+}
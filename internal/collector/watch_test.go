@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+)
+
+func TestWatch(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddChannel("C1", "alerts")
+	srv.AddChannel("C2", "random")
+	parent := newMessage("1736935200.000100", "1736935200.000100", "U2", "parent")
+	srv.AddMessage("C1", parent)
+	srv.AddMessage("C1", newMessage("1736935260.000200", "1736935200.000100", "U2", "earlier reply"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan []model.Thread, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, srv.Client(), WatchOptions{
+			List: ListOptions{
+				Author:       "U1",
+				Channels:     []string{"alerts"},
+				WithThread:   true,
+				WorkspaceURL: "https://example.slack.com/",
+			},
+			AppToken: "xapp-test",
+		}, func(threads []model.Thread) {
+			received <- threads
+		})
+	}()
+
+	next := func() []model.Thread {
+		t.Helper()
+		select {
+		case threads := <-received:
+			return threads
+		case <-time.After(5 * time.Second):
+			t.Fatal("no messages received")
+			return nil
+		}
+	}
+
+	// Filtered out by author and channel; the match in a thread brings the
+	// whole thread
+	srv.SendMessage("C1", newMessage("1736935300.000300", "", "U2", "other author"))
+	srv.SendMessage("C2", newMessage("1736935400.000400", "", "U1", "other channel"))
+	reply := newMessage("1736935500.000500", "1736935200.000100", "U1", "reply")
+	srv.AddMessage("C1", reply)
+	srv.SendMessage("C1", reply)
+
+	threads := next()
+	if len(threads) != 1 || threads[0].ThreadID != "1736935200.000100" || len(threads[0].Messages) != 3 {
+		t.Fatalf("first batch = %+v, want the whole thread of 3 messages", threads)
+	}
+	if threads[0].Channel != "alerts" || threads[0].ThreadPermalink == "" {
+		t.Errorf("thread channel = %q, permalink = %q", threads[0].Channel, threads[0].ThreadPermalink)
+	}
+
+	// Reconnects after the connection drops, and keeps later replies of the
+	// matched thread whatever their author
+	srv.DropConnections()
+	waitFor(t, func() bool { return srv.Calls("apps.connections.open") >= 2 })
+	srv.SendMessage("C1", newMessage("1736935600.000600", "1736935200.000100", "U3", "later reply"))
+
+	threads = next()
+	if len(threads) != 1 || len(threads[0].Messages) != 1 || threads[0].Messages[0].ID != "1736935600.000600" {
+		t.Fatalf("second batch = %+v, want the later reply", threads)
+	}
+
+	// Every event is acknowledged, kept or not
+	waitFor(t, func() bool { return len(srv.Acks()) == 4 })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Watch() error = %v", err)
+	}
+}
+
+func TestWatchRequiresAppToken(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	err := Watch(context.Background(), srv.Client(), WatchOptions{}, func([]model.Thread) {})
+	if err == nil {
+		t.Error("Watch() error = nil, want an error without an app token")
+	}
+}
+
+// waitFor polls cond until it holds or a few seconds pass
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
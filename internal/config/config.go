@@ -1,32 +1,197 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 )
 
+// DefaultOutputDir is the directory list writes its logs under
+const DefaultOutputDir = "logs"
+
 type Config struct {
-	Token   string
-	Author  string
-	Mention []string
+	// Profile is the name of the profile the settings come from, if any
+	Profile   string
+	Token     string
+	Author    string
+	Mention   []string
+	Channels  []string
+	OutputDir string
+	// AppToken is the app-level token (xapp-) Socket Mode connects with
+	AppToken string
+}
+
+// Profile holds the settings of one workspace in the config file
+type Profile struct {
+	Token string `json:"token,omitempty"`
+	// TokenEnv names an environment variable holding the token, to keep it
+	// out of the file
+	TokenEnv  string   `json:"token_env,omitempty"`
+	Author    string   `json:"author,omitempty"`
+	Mention   []string `json:"mention,omitempty"`
+	Channels  []string `json:"channels,omitempty"`
+	OutputDir string   `json:"output_dir,omitempty"`
+	// AppToken and AppTokenEnv set the app-level token like Token and
+	// TokenEnv
+	AppToken    string `json:"app_token,omitempty"`
+	AppTokenEnv string `json:"app_token_env,omitempty"`
+}
+
+// File is the config file, $XDG_CONFIG_HOME/slago/config.json by default
+type File struct {
+	DefaultProfile string             `json:"default_profile,omitempty"`
+	Profiles       map[string]Profile `json:"profiles,omitempty"`
+}
+
+// Path returns the config file path, $SLAGO_CONFIG if set
+func Path() (string, error) {
+	if path := os.Getenv("SLAGO_CONFIG"); path != "" {
+		return path, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "slago", "config.json"), nil
+}
+
+// ReadFile reads a config file. A missing file has no profiles.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Load reads the settings of the named profile. Without a name,
+// $SLAGO_PROFILE or the file's default profile is used when set, and
+// without any profile the settings come from the environment.
+func Load(profile string) (*Config, error) {
+	f, err := readDefaultFile()
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == "" {
+		profile = os.Getenv("SLAGO_PROFILE")
+	}
+	if profile == "" {
+		profile = f.DefaultProfile
+	}
+	if profile == "" {
+		return fromEnv(), nil
+	}
+
+	p, ok := f.Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", profile)
+	}
+	return fromProfile(profile, p), nil
 }
 
-func Load() (*Config, error) {
+// LoadAll loads every profile of the config file, sorted by name, like
+// Load does
+func LoadAll() ([]*Config, error) {
+	f, err := readDefaultFile()
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Profiles) == 0 {
+		return nil, fmt.Errorf("no profiles configured")
+	}
+
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cfgs := make([]*Config, 0, len(names))
+	for _, name := range names {
+		cfgs = append(cfgs, fromProfile(name, f.Profiles[name]))
+	}
+	return cfgs, nil
+}
+
+func fromEnv() *Config {
 	cfg := &Config{
-		Token:  os.Getenv("SLACK_API_TOKEN"),
-		Author: os.Getenv("SLACK_AUTHOR"),
+		Token:     os.Getenv("SLACK_API_TOKEN"),
+		Author:    os.Getenv("SLACK_AUTHOR"),
+		OutputDir: DefaultOutputDir,
+		AppToken:  os.Getenv("SLACK_APP_TOKEN"),
 	}
 
 	if mention := os.Getenv("SLACK_MENTION"); mention != "" {
 		cfg.Mention = strings.Split(mention, ",")
 	}
 
-	return cfg, nil
+	return cfg
+}
+
+// fromProfile builds the settings of a profile without the environment,
+// which may belong to another workspace. The profile writes under its own
+// directory below DefaultOutputDir unless it sets one.
+func fromProfile(name string, p Profile) *Config {
+	cfg := &Config{OutputDir: filepath.Join(DefaultOutputDir, name)}
+	cfg.apply(name, p)
+	return cfg
+}
+
+func readDefaultFile() (*File, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return ReadFile(path)
+}
+
+// apply overrides the settings with those set in a profile
+func (c *Config) apply(name string, p Profile) {
+	c.Profile = name
+	if p.TokenEnv != "" {
+		c.Token = os.Getenv(p.TokenEnv)
+	}
+	if p.Token != "" {
+		c.Token = p.Token
+	}
+	if p.AppTokenEnv != "" {
+		c.AppToken = os.Getenv(p.AppTokenEnv)
+	}
+	if p.AppToken != "" {
+		c.AppToken = p.AppToken
+	}
+	if p.Author != "" {
+		c.Author = p.Author
+	}
+	if len(p.Mention) > 0 {
+		c.Mention = p.Mention
+	}
+	if len(p.Channels) > 0 {
+		c.Channels = p.Channels
+	}
+	if p.OutputDir != "" {
+		c.OutputDir = p.OutputDir
+	}
 }
 
 func (c *Config) Validate() error {
 	if c.Token == "" {
+		if c.Profile != "" {
+			return fmt.Errorf("slack API token is required for profile %q (set token or token_env in the config file)", c.Profile)
+		}
 		return fmt.Errorf("slack API token is required (set SLACK_API_TOKEN or use --token flag)")
 	}
 	return nil
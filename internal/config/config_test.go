@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testConfig = `{
+  "default_profile": "acme",
+  "profiles": {
+    "acme": {"token": "xoxp-acme", "author": "me", "channels": ["general"]},
+    "beta": {"token_env": "BETA_TOKEN", "app_token_env": "BETA_APP_TOKEN", "mention": ["@team"], "output_dir": "out/beta"}
+  }
+}`
+
+func writeConfig(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SLAGO_CONFIG", path)
+	t.Setenv("SLAGO_PROFILE", "")
+	t.Setenv("SLACK_API_TOKEN", "xoxp-env")
+	t.Setenv("SLACK_AUTHOR", "")
+	t.Setenv("SLACK_MENTION", "U1")
+	t.Setenv("BETA_TOKEN", "xoxb-beta")
+	t.Setenv("SLACK_APP_TOKEN", "xapp-env")
+	t.Setenv("BETA_APP_TOKEN", "xapp-beta")
+}
+
+func TestLoad(t *testing.T) {
+	writeConfig(t)
+
+	tests := []struct {
+		name        string
+		profile     string
+		wantToken   string
+		wantApp     string
+		wantAuthor  string
+		wantMention []string
+		wantOutput  string
+		wantErr     bool
+	}{
+		{name: "default profile", wantToken: "xoxp-acme", wantAuthor: "me", wantOutput: "logs/acme"},
+		{name: "named profile", profile: "beta", wantToken: "xoxb-beta", wantApp: "xapp-beta", wantMention: []string{"@team"}, wantOutput: "out/beta"},
+		{name: "unknown profile", profile: "gamma", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.Token != tt.wantToken || cfg.Author != tt.wantAuthor || cfg.OutputDir != tt.wantOutput {
+				t.Errorf("Load() = %+v, want token %q, author %q, output %q", cfg, tt.wantToken, tt.wantAuthor, tt.wantOutput)
+			}
+			if cfg.AppToken != tt.wantApp {
+				t.Errorf("Load() AppToken = %q, want %q", cfg.AppToken, tt.wantApp)
+			}
+			if !slices.Equal(cfg.Mention, tt.wantMention) {
+				t.Errorf("Load() Mention = %v, want %v", cfg.Mention, tt.wantMention)
+			}
+		})
+	}
+}
+
+func TestLoadProfileRequiresToken(t *testing.T) {
+	writeConfig(t)
+	t.Setenv("BETA_TOKEN", "")
+
+	cfg, err := Load("beta")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("Validate() error = nil, want beta to need its own token over SLACK_API_TOKEN")
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	t.Setenv("SLAGO_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	t.Setenv("SLAGO_PROFILE", "")
+	t.Setenv("SLACK_API_TOKEN", "xoxp-env")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Token != "xoxp-env" || cfg.Profile != "" || cfg.OutputDir != DefaultOutputDir {
+		t.Errorf("Load() = %+v, want the environment's token and no profile", cfg)
+	}
+}
+
+func TestLoadAll(t *testing.T) {
+	writeConfig(t)
+
+	cfgs, err := LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+
+	var got []string
+	for _, cfg := range cfgs {
+		got = append(got, cfg.Profile+":"+cfg.Token+":"+cfg.OutputDir)
+	}
+	want := []string{"acme:xoxp-acme:logs/acme", "beta:xoxb-beta:out/beta"}
+	if !slices.Equal(got, want) {
+		t.Errorf("LoadAll() = %v, want %v", got, want)
+	}
+	if acme := cfgs[0]; acme.Mention != nil || acme.AppToken != "" {
+		t.Errorf("LoadAll() acme = %+v, want no settings from the environment", acme)
+	}
+}
+
+func TestLoadAllRequiresProfileToken(t *testing.T) {
+	writeConfig(t)
+	t.Setenv("BETA_TOKEN", "")
+
+	cfgs, err := LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+	if err := cfgs[1].Validate(); err == nil {
+		t.Errorf("Validate() error = nil, want beta to need its own token over SLACK_API_TOKEN")
+	}
+}
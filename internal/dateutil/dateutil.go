@@ -2,6 +2,7 @@ package dateutil
 
 import (
 	"fmt"
+	"path/filepath"
 	"time"
 )
 
@@ -76,7 +77,7 @@ func FormatDate(t time.Time) string {
 	return t.Format("2006-01-02")
 }
 
-// OutputPath returns the output path for a given date
-func OutputPath(t time.Time) string {
-	return fmt.Sprintf("logs/%d/%02d/%02d/slack.json", t.Year(), t.Month(), t.Day())
+// OutputPath returns the output path for a given date under the root directory
+func OutputPath(root string, t time.Time) string {
+	return filepath.Join(root, fmt.Sprintf("%d/%02d/%02d/slack.json", t.Year(), t.Month(), t.Day()))
 }
@@ -118,9 +118,16 @@ func TestDateRange_Days(t *testing.T) {
 
 func TestOutputPath(t *testing.T) {
 	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
-	want := "logs/2025/01/15/slack.json"
-	got := OutputPath(date)
-	if got != want {
-		t.Errorf("OutputPath() = %v, want %v", got, want)
+	tests := []struct {
+		root string
+		want string
+	}{
+		{"logs", "logs/2025/01/15/slack.json"},
+		{"logs/acme", "logs/acme/2025/01/15/slack.json"},
+	}
+	for _, tt := range tests {
+		if got := OutputPath(tt.root, date); got != tt.want {
+			t.Errorf("OutputPath(%q) = %v, want %v", tt.root, got, tt.want)
+		}
 	}
 }
@@ -0,0 +1,114 @@
+// Package doctor checks the scopes of a Slack token against what slago's
+// commands and flags need
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/longkey1/slago/internal/slack"
+	slackapi "github.com/slack-go/slack"
+)
+
+// Requirement is a scope needed by some of slago's commands and flags
+type Requirement struct {
+	Scope string
+	// Usage names the commands and flags that need the scope
+	Usage string
+	// Optional scopes only matter when their flags are used
+	Optional bool
+	// UserOnly scopes cannot be granted to bot tokens
+	UserOnly bool
+}
+
+// Requirements lists the scopes slago uses
+var Requirements = []Requirement{
+	{Scope: "search:read", Usage: "list (default --source search)", UserOnly: true},
+	{Scope: "channels:history", Usage: "get, list --thread, list --source history"},
+	{Scope: "channels:read", Usage: "channel names, list --source history, cache refresh"},
+	{Scope: "groups:history", Usage: "get and list --source history in private channels", Optional: true},
+	{Scope: "groups:read", Usage: "private channel names", Optional: true},
+	{Scope: "users:read", Usage: "--resolve-users (on by default), --author @handle, default --tz, cache refresh"},
+	{Scope: "users:read.email", Usage: "--author <email>", Optional: true},
+	{Scope: "usergroups:read", Usage: "--mention @group", Optional: true},
+	{Scope: "im:read", Usage: "--include-dms", Optional: true},
+	{Scope: "mpim:read", Usage: "--include-mpdms", Optional: true},
+	{Scope: "im:history", Usage: "get in DMs, --include-dms with --thread or --source history", Optional: true},
+	{Scope: "mpim:history", Usage: "get in group DMs, --include-mpdms with --thread or --source history", Optional: true},
+	{Scope: "reactions:read", Usage: "--reactions, --has-reaction", Optional: true},
+	{Scope: "files:read", Usage: "--download-files", Optional: true},
+}
+
+// Result is the outcome of checking one requirement
+type Result struct {
+	Requirement
+	Granted bool
+	// Advice says how to fix a missing scope
+	Advice string
+}
+
+// Check compares the granted scopes with Requirements
+func Check(tokenType string, scopes []string) []Result {
+	results := make([]Result, 0, len(Requirements))
+	for _, req := range Requirements {
+		result := Result{Requirement: req, Granted: slices.Contains(scopes, req.Scope)}
+		if req.UserOnly && tokenType != slack.TokenTypeUser {
+			// Other tokens work with the alternatives named in the advice
+			result.Optional = true
+		}
+		if !result.Granted {
+			result.Advice = scopeAdvice(tokenType, req)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// MissingRequired counts the results of required scopes that are missing
+func MissingRequired(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if !r.Granted && !r.Optional {
+			n++
+		}
+	}
+	return n
+}
+
+func scopeAdvice(tokenType string, req Requirement) string {
+	switch {
+	case req.UserOnly && tokenType != slack.TokenTypeUser:
+		return fmt.Sprintf("%s is only granted to user tokens: use a user token (xoxp-) or --source history", req.Scope)
+	case tokenType == slack.TokenTypeBot:
+		return fmt.Sprintf("add %s to the app's Bot Token Scopes (OAuth & Permissions) and reinstall the app", req.Scope)
+	default:
+		return fmt.Sprintf("add %s to the app's User Token Scopes (OAuth & Permissions) and reinstall the app", req.Scope)
+	}
+}
+
+// ErrorAdvice says how to fix a failed auth.test call
+func ErrorAdvice(tokenType string, rotating bool, err error) string {
+	if tokenType == slack.TokenTypeApp {
+		return "app-level tokens (xapp-) cannot call the Web API: use a user (xoxp-) or bot (xoxb-) token"
+	}
+
+	var slackErr slackapi.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return "check the network connection to slack.com"
+	}
+	switch slackErr.Err {
+	case "not_authed", "invalid_auth":
+		return "the token is not valid: copy it again from the app's OAuth & Permissions page"
+	case "token_expired":
+		if rotating {
+			return "the rotating token has expired: refresh it with oauth.v2.access and the refresh token"
+		}
+		return "the token has expired: reinstall the app to get a new one"
+	case "token_revoked", "account_inactive":
+		return "the token has been revoked: reinstall the app to get a new one"
+	case "not_allowed_token_type":
+		return "this token type cannot call the Web API: use a user (xoxp-) or bot (xoxb-) token"
+	}
+	return "see https://api.slack.com/methods/auth.test for the meaning of the error"
+}
@@ -0,0 +1,81 @@
+package doctor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/longkey1/slago/internal/slack"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenType   string
+		scopes      []string
+		wantMissing int
+		wantAdvice  map[string]string
+	}{
+		{
+			name:        "user token with required scopes",
+			tokenType:   slack.TokenTypeUser,
+			scopes:      []string{"search:read", "channels:history", "channels:read", "users:read"},
+			wantMissing: 0,
+			wantAdvice:  map[string]string{"files:read": "User Token Scopes"},
+		},
+		{
+			name:        "bot token cannot search",
+			tokenType:   slack.TokenTypeBot,
+			scopes:      []string{"channels:history"},
+			wantMissing: 2,
+			wantAdvice: map[string]string{
+				"search:read":   "--source history",
+				"channels:read": "Bot Token Scopes",
+				"users:read":    "Bot Token Scopes",
+				"im:history":    "Bot Token Scopes",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Check(tt.tokenType, tt.scopes)
+			if got := MissingRequired(results); got != tt.wantMissing {
+				t.Errorf("MissingRequired() = %d, want %d", got, tt.wantMissing)
+			}
+			for _, r := range results {
+				want, ok := tt.wantAdvice[r.Scope]
+				if !ok {
+					continue
+				}
+				if r.Granted || !strings.Contains(r.Advice, want) {
+					t.Errorf("%s: granted = %v, advice = %q, want advice containing %q", r.Scope, r.Granted, r.Advice, want)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorAdvice(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenType string
+		rotating  bool
+		err       error
+		want      string
+	}{
+		{"invalid token", slack.TokenTypeUser, false, slackapi.SlackErrorResponse{Err: "invalid_auth"}, "not valid"},
+		{"expired rotating token", slack.TokenTypeUser, true, slackapi.SlackErrorResponse{Err: "token_expired"}, "refresh token"},
+		{"app token", slack.TokenTypeApp, false, slackapi.SlackErrorResponse{Err: "not_allowed_token_type"}, "xapp-"},
+		{"network error", slack.TokenTypeBot, false, errors.New("dial tcp: timeout"), "network"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorAdvice(tt.tokenType, tt.rotating, tt.err); !strings.Contains(got, tt.want) {
+				t.Errorf("ErrorAdvice() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+// Channel types
+const (
+	ChannelTypePublic  = "channel"
+	ChannelTypePrivate = "private_channel"
+	ChannelTypeIM      = "im"
+	ChannelTypeMPIM    = "mpim"
+)
+
+// Channel represents a Slack conversation
+type Channel struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	IsMember  bool   `json:"is_member,omitempty"`
+	IsPrivate bool   `json:"is_private,omitempty"`
+	IsIM      bool   `json:"is_im,omitempty"`
+	IsMPIM    bool   `json:"is_mpim,omitempty"`
+	User      string `json:"user,omitempty"`
+}
+
+// Type returns the conversation type
+func (c Channel) Type() string {
+	switch {
+	case c.IsIM:
+		return ChannelTypeIM
+	case c.IsMPIM:
+		return ChannelTypeMPIM
+	case c.IsPrivate:
+		return ChannelTypePrivate
+	default:
+		return ChannelTypePublic
+	}
+}
+
+// IsDirect reports whether the conversation is a DM or group DM
+func (c Channel) IsDirect() bool {
+	return c.IsIM || c.IsMPIM
+}
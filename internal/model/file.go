@@ -0,0 +1,14 @@
+package model
+
+// File is a file shared in a message
+type File struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Title      string `json:"title,omitempty"`
+	Mimetype   string `json:"mimetype,omitempty"`
+	Size       int64  `json:"size"`
+	URLPrivate string `json:"url_private,omitempty"`
+	Permalink  string `json:"permalink,omitempty"`
+	// LocalPath is where the file was downloaded, relative to the JSON output
+	LocalPath string `json:"local_path,omitempty"`
+}
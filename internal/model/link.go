@@ -0,0 +1,38 @@
+package model
+
+import "encoding/json"
+
+// Link sources
+const (
+	LinkSourceText       = "text"
+	LinkSourceAttachment = "attachment"
+	LinkSourceUnfurl     = "unfurl"
+)
+
+// Link is a link found in a message, with the title and description of its
+// attachment or unfurl when Slack provided one
+type Link struct {
+	URL    string `json:"url"`
+	Label  string `json:"label,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	Source string `json:"source,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+// UnmarshalJSON also accepts the plain URL strings written by older versions
+func (l *Link) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*l = Link{URL: url}
+		return nil
+	}
+
+	type link Link
+	var v link
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*l = Link(v)
+	return nil
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLinkUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Link
+	}{
+		{
+			name:  "structured",
+			input: `{"url":"https://x.com","label":"x","domain":"x.com","source":"text"}`,
+			want:  Link{URL: "https://x.com", Label: "x", Domain: "x.com", Source: LinkSourceText},
+		},
+		{
+			name:  "legacy URL",
+			input: `"https://x.com"`,
+			want:  Link{URL: "https://x.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Link
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,32 +1,72 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
 
 // Message represents a Slack message
 type Message struct {
-	ID             string    `json:"id"`
-	Type           string    `json:"type"`
-	Content        string    `json:"content"`
-	Author         string    `json:"author"`
-	Timestamp      time.Time `json:"timestamp"`
-	Channel        string    `json:"channel"`
-	ChannelID      string    `json:"channel_id"`
-	Permalink      string    `json:"permalink,omitempty"`
-	Mentions       []string  `json:"mentions,omitempty"`
-	AttachedLinks  []string  `json:"attached_links,omitempty"`
-	ThreadTS       string    `json:"thread_ts"`
-	IsThreadParent bool      `json:"is_thread_parent"`
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	Content           string     `json:"content"`
+	ContentText       string     `json:"content_text,omitempty"`
+	ContentMarkdown   string     `json:"content_markdown,omitempty"`
+	Author            string     `json:"author"`
+	AuthorName        string     `json:"author_name,omitempty"`
+	AuthorDisplayName string     `json:"author_display_name,omitempty"`
+	AuthorRealName    string     `json:"author_real_name,omitempty"`
+	Subtype           string     `json:"subtype,omitempty"`
+	BotID             string     `json:"bot_id,omitempty"`
+	BotName           string     `json:"bot_name,omitempty"`
+	Timestamp         time.Time  `json:"timestamp"`
+	Channel           string     `json:"channel"`
+	ChannelID         string     `json:"channel_id"`
+	ChannelType       string     `json:"channel_type,omitempty"`
+	IsPrivate         bool       `json:"is_private,omitempty"`
+	Permalink         string     `json:"permalink,omitempty"`
+	Mentions          []Mention  `json:"mentions,omitempty"`
+	AttachedLinks     []Link     `json:"attached_links,omitempty"`
+	ThreadTS          string     `json:"thread_ts"`
+	IsThreadParent    bool       `json:"is_thread_parent"`
+	ReplyCount        int        `json:"reply_count,omitempty"`
+	LatestReply       string     `json:"latest_reply,omitempty"`
+	Reactions         []Reaction `json:"reactions,omitempty"`
+	Files             []File     `json:"files,omitempty"`
+	EditedAt          *time.Time `json:"edited_at,omitempty"`
+	EditedBy          string     `json:"edited_by,omitempty"`
+	CollectedAt       *time.Time `json:"collected_at,omitempty"`
+	Revisions         []Revision `json:"revisions,omitempty"`
+	// Blocks holds the raw Block Kit blocks when they are kept
+	Blocks json.RawMessage `json:"blocks,omitempty"`
+}
+
+// Revision is an earlier text of an edited message
+type Revision struct {
+	Content  string     `json:"content"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	EditedBy string     `json:"edited_by,omitempty"`
+}
+
+// Reaction is an emoji reaction on a message
+type Reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users,omitempty"`
 }
 
 // Thread represents a Slack thread with its messages
 type Thread struct {
-	ThreadID       string    `json:"thread_id"`
-	ThreadPermalink string   `json:"thread_permalink,omitempty"`
-	Channel        string    `json:"channel,omitempty"`
-	ChannelID      string    `json:"channel_id,omitempty"`
-	Messages       []Message `json:"messages"`
-	MessageCount   int       `json:"message_count,omitempty"`
-	ThreadCount    int       `json:"thread_count,omitempty"`
+	ThreadID        string    `json:"thread_id"`
+	ThreadPermalink string    `json:"thread_permalink,omitempty"`
+	Channel         string    `json:"channel,omitempty"`
+	ChannelID       string    `json:"channel_id,omitempty"`
+	ChannelType     string    `json:"channel_type,omitempty"`
+	IsPrivate       bool      `json:"is_private,omitempty"`
+	Messages        []Message `json:"messages"`
+	MessageCount    int       `json:"message_count,omitempty"`
+	ThreadCount     int       `json:"thread_count,omitempty"`
 }
 
 // SearchResult represents the result of a search operation
@@ -34,3 +74,87 @@ type SearchResult struct {
 	Threads []Thread `json:"threads,omitempty"`
 	Thread  *Thread  `json:"thread,omitempty"`
 }
+
+// HasReaction reports whether the message has a reaction with the given emoji
+// name. Skin tone variants such as "+1::skin-tone-2" match their base name.
+func (m Message) HasReaction(name string) bool {
+	name = strings.Trim(name, ":")
+	for _, r := range m.Reactions {
+		base, _, _ := strings.Cut(r.Name, "::")
+		if r.Name == name || base == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBot reports whether the message was posted by a bot or integration
+func (m Message) IsBot() bool {
+	return m.BotID != "" || m.Subtype == "bot_message" || (m.Author == "" && m.BotName != "")
+}
+
+// IsNewerThan reports whether m is a more recent copy of the same message
+// than other: edited later, or collected later. Search copies never carry
+// an edit time, so a copy without one is only older than an edited copy
+// when it was collected before the edit.
+func (m Message) IsNewerThan(other Message) bool {
+	switch {
+	case m.EditedAt != nil && other.EditedAt != nil:
+		if c := m.EditedAt.Compare(*other.EditedAt); c != 0 {
+			return c > 0
+		}
+	case m.EditedAt != nil:
+		return compareTimes(m.EditedAt, other.CollectedAt) > 0
+	case other.EditedAt != nil:
+		return compareTimes(m.CollectedAt, other.EditedAt) > 0
+	}
+	return compareTimes(m.CollectedAt, other.CollectedAt) > 0
+}
+
+// compareTimes orders optional times, unset first
+func compareTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(*b)
+}
+
+// TS returns the message timestamp
+func (m Message) TS() TS {
+	return TS(m.ID)
+}
+
+// CompareMessages orders messages by timestamp, breaking ties by channel
+// so that the order is deterministic
+func CompareMessages(a, b Message) int {
+	if c := a.TS().Compare(b.TS()); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ChannelID, b.ChannelID)
+}
+
+// CompareThreads orders threads by their first message, breaking ties by
+// channel and thread ID. Threads without messages sort first.
+func CompareThreads(a, b Thread) int {
+	switch {
+	case len(a.Messages) == 0 && len(b.Messages) == 0:
+	case len(a.Messages) == 0:
+		return -1
+	case len(b.Messages) == 0:
+		return 1
+	default:
+		if c := CompareMessages(a.Messages[0], b.Messages[0]); c != 0 {
+			return c
+		}
+	}
+
+	if c := strings.Compare(a.ChannelID, b.ChannelID); c != 0 {
+		return c
+	}
+	return TS(a.ThreadID).Compare(TS(b.ThreadID))
+}
@@ -0,0 +1,29 @@
+package model
+
+import "testing"
+
+func TestMessageHasReaction(t *testing.T) {
+	msg := Message{Reactions: []Reaction{
+		{Name: "eyes", Count: 1},
+		{Name: "+1::skin-tone-3", Count: 2},
+	}}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "eyes", want: true},
+		{name: ":eyes:", want: true},
+		{name: "+1", want: true},
+		{name: "+1::skin-tone-3", want: true},
+		{name: "white_check_mark", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := msg.HasReaction(tt.name); got != tt.want {
+				t.Errorf("HasReaction(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TS is a Slack message timestamp ("seconds.microseconds") kept verbatim.
+// It identifies a message within a channel and orders messages exactly,
+// including messages posted within the same second.
+type TS string
+
+// TSFromTime converts a time to a Slack timestamp
+func TSFromTime(t time.Time) TS {
+	return TS(fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/1000))
+}
+
+// parts splits the timestamp into seconds and microseconds
+func (ts TS) parts() (sec, micro int64, ok bool) {
+	secPart, fracPart, _ := strings.Cut(string(ts), ".")
+	sec, err := strconv.ParseInt(secPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if fracPart == "" {
+		return sec, 0, true
+	}
+	if len(fracPart) > 6 {
+		fracPart = fracPart[:6]
+	}
+	fracPart += strings.Repeat("0", 6-len(fracPart))
+	micro, err = strconv.ParseInt(fracPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return sec, micro, true
+}
+
+// Valid reports whether the timestamp can be parsed
+func (ts TS) Valid() bool {
+	_, _, ok := ts.parts()
+	return ok
+}
+
+// Time returns the timestamp in UTC with microsecond precision
+func (ts TS) Time() time.Time {
+	sec, micro, ok := ts.parts()
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(sec, micro*int64(time.Microsecond)).UTC()
+}
+
+// Compare returns -1, 0 or +1 depending on whether ts is before, equal to or
+// after other. Unparsable timestamps sort before valid ones.
+func (ts TS) Compare(other TS) int {
+	s1, m1, ok1 := ts.parts()
+	s2, m2, ok2 := other.parts()
+
+	switch {
+	case !ok1 && !ok2:
+		return strings.Compare(string(ts), string(other))
+	case !ok1:
+		return -1
+	case !ok2:
+		return 1
+	case s1 != s2:
+		return cmpInt(s1, s2)
+	default:
+		return cmpInt(m1, m2)
+	}
+}
+
+// Before reports whether ts is before other
+func (ts TS) Before(other TS) bool {
+	return ts.Compare(other) < 0
+}
+
+// After reports whether ts is after other
+func (ts TS) After(other TS) bool {
+	return ts.Compare(other) > 0
+}
+
+// String returns the raw timestamp
+func (ts TS) String() string {
+	return string(ts)
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
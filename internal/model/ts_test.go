@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTSTime(t *testing.T) {
+	tests := []struct {
+		ts   TS
+		want time.Time
+	}{
+		{"1716192523.567890", time.Unix(1716192523, 567890000)},
+		{"1716192523.000001", time.Unix(1716192523, 1000)},
+		{"1716192523", time.Unix(1716192523, 0)},
+		{"invalid", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.ts.Time(); !got.Equal(tt.want) {
+			t.Errorf("TS(%q).Time() = %v, want %v", tt.ts, got, tt.want)
+		}
+	}
+}
+
+func TestTSCompare(t *testing.T) {
+	tests := []struct {
+		a, b TS
+		want int
+	}{
+		{"1716192523.000100", "1716192523.000200", -1},
+		{"1716192523.000200", "1716192523.000100", 1},
+		{"1716192523.000100", "1716192523.000100", 0},
+		{"1716192523.1", "1716192523.000200", 1},
+		{"999999999.999999", "1000000000.000000", -1},
+		{"invalid", "1716192523.000100", -1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Compare(tt.b); got != tt.want {
+			t.Errorf("TS(%q).Compare(%q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTSFromTime(t *testing.T) {
+	want := TS("1716192523.567890")
+	if got := TSFromTime(want.Time()); got != want {
+		t.Errorf("TSFromTime() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+import "encoding/json"
+
+// User represents a Slack user profile
+type User struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	RealName    string `json:"real_name,omitempty"`
+}
+
+// Label returns the name Slack would show for the user
+func (u User) Label() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.RealName != "" {
+		return u.RealName
+	}
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.ID
+}
+
+// Mention types
+const (
+	MentionTypeUser      = "user"
+	MentionTypeUserGroup = "usergroup"
+	MentionTypeBroadcast = "broadcast"
+	MentionTypeChannel   = "channel"
+)
+
+// Mention represents a mention found in a message. Broadcasts use the
+// range ("here", "channel" or "everyone") as their ID.
+type Mention struct {
+	Type string `json:"type,omitempty"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// IsUser reports whether the mention is of a user. Mentions written by
+// older versions have no type and were always users.
+func (m Mention) IsUser() bool {
+	return m.Type == MentionTypeUser || m.Type == ""
+}
+
+// UnmarshalJSON also accepts the plain label strings written by older versions
+func (m *Mention) UnmarshalJSON(data []byte) error {
+	var label string
+	if err := json.Unmarshal(data, &label); err == nil {
+		*m = Mention{Name: label}
+		return nil
+	}
+
+	type mention Mention
+	var v mention
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*m = Mention(v)
+	return nil
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMentionUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Mention
+	}{
+		{
+			name:  "structured",
+			input: `{"id":"U123","name":"alice"}`,
+			want:  Mention{ID: "U123", Name: "alice"},
+		},
+		{
+			name:  "legacy label",
+			input: `"alice"`,
+			want:  Mention{Name: "alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Mention
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
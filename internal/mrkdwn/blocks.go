@@ -0,0 +1,249 @@
+package mrkdwn
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// RenderRawBlocks renders Block Kit blocks kept as JSON, see RenderBlocks
+func RenderRawBlocks(raw json.RawMessage, lookup Lookup) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var blocks slack.Blocks
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return ""
+	}
+	return RenderBlocks(blocks, lookup)
+}
+
+// RenderBlocks renders the rich_text blocks of a message as Markdown.
+// Other block types are ignored; without rich_text blocks it returns "".
+func RenderBlocks(blocks slack.Blocks, lookup Lookup) string {
+	if lookup == nil {
+		lookup = NoLookup{}
+	}
+
+	var parts []string
+	for _, block := range blocks.BlockSet {
+		rt, ok := block.(*slack.RichTextBlock)
+		if !ok {
+			continue
+		}
+		if md := renderRichText(rt.Elements, lookup); md != "" {
+			parts = append(parts, md)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func renderRichText(elements []slack.RichTextElement, lookup Lookup) string {
+	var b strings.Builder
+	// Ordered list numbering continues across consecutive lists per indent
+	counters := make(map[int]int)
+	prevList := false
+
+	for _, elem := range elements {
+		var chunk string
+		isList := false
+
+		switch e := elem.(type) {
+		case *slack.RichTextSection:
+			chunk = renderSection(e.Elements, lookup)
+		case *slack.RichTextList:
+			chunk = renderList(e, counters, lookup)
+			isList = true
+		case *slack.RichTextQuote:
+			chunk = renderQuote(e.Elements, lookup)
+		case *slack.RichTextPreformatted:
+			chunk = renderPreformatted(e.Elements, lookup)
+		}
+
+		if !isList {
+			counters = make(map[int]int)
+		}
+		chunk = strings.TrimRight(chunk, "\n")
+		if chunk == "" {
+			continue
+		}
+
+		if b.Len() > 0 {
+			if isList && prevList {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(chunk)
+		prevList = isList
+	}
+	return b.String()
+}
+
+func renderList(list *slack.RichTextList, counters map[int]int, lookup Lookup) string {
+	// Deeper levels restart their numbering under a new parent item
+	for indent := range counters {
+		if indent > list.Indent {
+			delete(counters, indent)
+		}
+	}
+	if _, ok := counters[list.Indent]; !ok {
+		counters[list.Indent] = list.Offset
+	}
+
+	prefix := strings.Repeat("    ", list.Indent)
+	var lines []string
+	for _, elem := range list.Elements {
+		section, ok := elem.(*slack.RichTextSection)
+		if !ok {
+			continue
+		}
+
+		marker := "-"
+		if list.Style == slack.RTEListOrdered {
+			counters[list.Indent]++
+			marker = strconv.Itoa(counters[list.Indent]) + "."
+		}
+
+		text := strings.TrimRight(renderSection(section.Elements, lookup), "\n")
+		// Continuation lines stay inside the list item
+		text = strings.ReplaceAll(text, "\n", "\n"+prefix+strings.Repeat(" ", len(marker)+1))
+		lines = append(lines, prefix+marker+" "+text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderQuote(elements []slack.RichTextSectionElement, lookup Lookup) string {
+	text := strings.TrimRight(renderSection(elements, lookup), "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderPreformatted(elements []slack.RichTextSectionElement, lookup Lookup) string {
+	var b strings.Builder
+	for _, elem := range elements {
+		switch e := elem.(type) {
+		case *slack.RichTextSectionTextElement:
+			b.WriteString(e.Text)
+		case *slack.RichTextSectionLinkElement:
+			if e.Text != "" {
+				b.WriteString(e.Text)
+			} else {
+				b.WriteString(e.URL)
+			}
+		default:
+			b.WriteString(plainElement(elem, lookup))
+		}
+	}
+
+	code := strings.TrimRight(b.String(), "\n")
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + code + "\n" + fence
+}
+
+func renderSection(elements []slack.RichTextSectionElement, lookup Lookup) string {
+	var b strings.Builder
+	for _, elem := range elements {
+		switch e := elem.(type) {
+		case *slack.RichTextSectionTextElement:
+			if e.Style != nil && e.Style.Code {
+				b.WriteString(styled(codeSpan(e.Text), e.Style))
+			} else {
+				b.WriteString(styled(markdownEscaper.Replace(e.Text), e.Style))
+			}
+		case *slack.RichTextSectionLinkElement:
+			b.WriteString(styled(link(e.URL, e.Text, formatMarkdown), e.Style))
+		default:
+			b.WriteString(markdownEscaper.Replace(plainElement(elem, lookup)))
+		}
+	}
+	return b.String()
+}
+
+// plainElement renders the non-text section elements
+func plainElement(elem slack.RichTextSectionElement, lookup Lookup) string {
+	switch e := elem.(type) {
+	case *slack.RichTextSectionUserElement:
+		if name, ok := lookup.UserName(e.UserID); ok {
+			return "@" + name
+		}
+		return "@" + e.UserID
+	case *slack.RichTextSectionUserGroupElement:
+		return "@" + e.UsergroupID
+	case *slack.RichTextSectionChannelElement:
+		if name, ok := lookup.ChannelName(e.ChannelID); ok {
+			return "#" + name
+		}
+		return "#" + e.ChannelID
+	case *slack.RichTextSectionBroadcastElement:
+		return "@" + e.Range
+	case *slack.RichTextSectionEmojiElement:
+		return emoji(e.Name, e.Unicode)
+	case *slack.RichTextSectionDateElement:
+		if e.Fallback != nil && *e.Fallback != "" {
+			return *e.Fallback
+		}
+		return e.Timestamp.Time().UTC().Format(time.RFC3339)
+	case *slack.RichTextSectionTeamElement:
+		return e.TeamID
+	case *slack.RichTextSectionColorElement:
+		return e.Value
+	}
+	return ""
+}
+
+// emoji turns an emoji element into its character, falling back to the
+// :name: shortcode for custom emoji
+func emoji(name, unicode string) string {
+	if unicode == "" {
+		return ":" + name + ":"
+	}
+	var b strings.Builder
+	for _, code := range strings.Split(unicode, "-") {
+		r, err := strconv.ParseInt(code, 16, 32)
+		if err != nil {
+			return ":" + name + ":"
+		}
+		b.WriteRune(rune(r))
+	}
+	return b.String()
+}
+
+// styled wraps text in the Markdown markers of style, keeping surrounding
+// whitespace outside the markers where CommonMark requires it
+func styled(text string, style *slack.RichTextSectionTextStyle) string {
+	if style == nil {
+		return text
+	}
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return text
+	}
+	start := strings.Index(text, core)
+	lead, trail := text[:start], text[start+len(core):]
+
+	if style.Strike {
+		core = "~~" + core + "~~"
+	}
+	if style.Italic {
+		core = "_" + core + "_"
+	}
+	if style.Bold {
+		core = "**" + core + "**"
+	}
+	return lead + core + trail
+}
@@ -0,0 +1,99 @@
+package mrkdwn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestRenderBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		blocks string
+		want   string
+	}{
+		{
+			name: "styled text and entities",
+			blocks: `[{"type":"rich_text","elements":[{"type":"rich_text_section","elements":[
+				{"type":"text","text":"Hi "},
+				{"type":"user","user_id":"U123"},
+				{"type":"text","text":" see "},
+				{"type":"text","text":"this ","style":{"bold":true}},
+				{"type":"text","text":"x*y","style":{"code":true}},
+				{"type":"text","text":" in "},
+				{"type":"channel","channel_id":"C42"},
+				{"type":"text","text":" "},
+				{"type":"emoji","name":"+1","unicode":"1f44d"},
+				{"type":"emoji","name":"partyparrot"},
+				{"type":"text","text":" "},
+				{"type":"broadcast","range":"here"},
+				{"type":"text","text":" 2*3","style":{"strike":true}}
+			]}]}]`,
+			want: "Hi @U123 see **this** `x*y` in #C42 👍:partyparrot: @here ~~2\\*3~~",
+		},
+		{
+			name: "links",
+			blocks: `[{"type":"rich_text","elements":[{"type":"rich_text_section","elements":[
+				{"type":"link","url":"https://example.com/a_(b)","text":"the docs"},
+				{"type":"text","text":" and "},
+				{"type":"link","url":"https://example.com"}
+			]}]}]`,
+			want: "[the docs](https://example.com/a_%28b%29) and <https://example.com>",
+		},
+		{
+			name: "lists continue numbering and nest",
+			blocks: `[{"type":"rich_text","elements":[
+				{"type":"rich_text_section","elements":[{"type":"text","text":"Steps:\n"}]},
+				{"type":"rich_text_list","style":"ordered","indent":0,"elements":[
+					{"type":"rich_text_section","elements":[{"type":"text","text":"one"}]}
+				]},
+				{"type":"rich_text_list","style":"bullet","indent":1,"elements":[
+					{"type":"rich_text_section","elements":[{"type":"text","text":"detail"}]}
+				]},
+				{"type":"rich_text_list","style":"ordered","indent":0,"elements":[
+					{"type":"rich_text_section","elements":[{"type":"text","text":"two"}]}
+				]}
+			]}]`,
+			want: "Steps:\n\n1. one\n    - detail\n2. two",
+		},
+		{
+			name: "quote and preformatted",
+			blocks: `[{"type":"rich_text","elements":[
+				{"type":"rich_text_quote","elements":[{"type":"text","text":"first\nsecond"}]},
+				{"type":"rich_text_preformatted","elements":[{"type":"text","text":"go test ./...\n*raw*"}]}
+			]}]`,
+			want: "> first\n> second\n\n```\ngo test ./...\n*raw*\n```",
+		},
+		{
+			name:   "no rich text",
+			blocks: `[{"type":"divider"}]`,
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var blocks slack.Blocks
+			if err := json.Unmarshal([]byte(tt.blocks), &blocks); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got := RenderBlocks(blocks, nil); got != tt.want {
+				t.Errorf("RenderBlocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRawBlocksResolvesNames(t *testing.T) {
+	raw := []byte(`[{"type":"rich_text","elements":[{"type":"rich_text_section","elements":[
+		{"type":"user","user_id":"U123"},
+		{"type":"text","text":" in "},
+		{"type":"channel","channel_id":"C123"}
+	]}]}]`)
+
+	want := "@alice in #general"
+	if got := RenderRawBlocks(raw, fakeLookup{}); got != want {
+		t.Errorf("RenderRawBlocks() = %q, want %q", got, want)
+	}
+}
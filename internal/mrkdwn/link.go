@@ -0,0 +1,60 @@
+package mrkdwn
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+var (
+	linkTokenPattern = regexp.MustCompile(`<(https?://[^<>|\s]+)(?:\|([^<>]*))?>`)
+	bareLinkPattern  = regexp.MustCompile(`https?://[^\s<>|]+`)
+)
+
+// Links extracts the links of a message text with their labels, in order of
+// appearance and without duplicates
+func Links(text string) []model.Link {
+	seen := make(map[string]bool)
+	var links []model.Link
+	add := func(rawURL, label string) {
+		rawURL = entityDecoder.Replace(rawURL)
+		if seen[rawURL] {
+			return
+		}
+		seen[rawURL] = true
+
+		label = entityDecoder.Replace(label)
+		if label == rawURL {
+			label = ""
+		}
+		links = append(links, model.Link{
+			URL:    rawURL,
+			Label:  label,
+			Domain: Domain(rawURL),
+			Source: model.LinkSourceText,
+		})
+	}
+
+	// Slack wraps links in angle brackets; bare URLs only appear in text
+	// from other sources
+	rest := linkTokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		m := linkTokenPattern.FindStringSubmatch(token)
+		add(m[1], m[2])
+		return " "
+	})
+	for _, link := range bareLinkPattern.FindAllString(rest, -1) {
+		add(link, "")
+	}
+	return links
+}
+
+// Domain returns the lower-cased host of a URL, or "" if it has none
+func Domain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,20 @@
+package mrkdwn
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+func TestLinks(t *testing.T) {
+	got := Links("see <https://x.com/a?b=1&amp;c=2|click here>, <https://Docs.Example.com> and <https://x.com/a?b=1&amp;c=2> or http://bare.example.org/path")
+	want := []model.Link{
+		{URL: "https://x.com/a?b=1&c=2", Label: "click here", Domain: "x.com", Source: model.LinkSourceText},
+		{URL: "https://Docs.Example.com", Domain: "docs.example.com", Source: model.LinkSourceText},
+		{URL: "http://bare.example.org/path", Domain: "bare.example.org", Source: model.LinkSourceText},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Links() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,49 @@
+package mrkdwn
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+var mentionPattern = regexp.MustCompile(`<([@#!][^<>|]*)(?:\|([^<>]*))?>`)
+
+// Mentions extracts the user, user group, broadcast and channel mentions of
+// a message text, in order of appearance and without duplicates. Names are
+// the labels embedded in the markup, when present.
+func Mentions(text string) []model.Mention {
+	seen := make(map[model.Mention]bool)
+	var mentions []model.Mention
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		mention, ok := parseMention(match[1], match[2])
+		if !ok {
+			continue
+		}
+		key := model.Mention{Type: mention.Type, ID: mention.ID}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		mentions = append(mentions, mention)
+	}
+	return mentions
+}
+
+func parseMention(target, label string) (model.Mention, bool) {
+	switch {
+	case strings.HasPrefix(target, "@"):
+		return model.Mention{Type: model.MentionTypeUser, ID: target[1:], Name: strings.TrimPrefix(label, "@")}, len(target) > 1
+	case strings.HasPrefix(target, "#"):
+		return model.Mention{Type: model.MentionTypeChannel, ID: target[1:], Name: label}, len(target) > 1
+	case strings.HasPrefix(target, "!subteam^"):
+		id := strings.TrimPrefix(target, "!subteam^")
+		return model.Mention{Type: model.MentionTypeUserGroup, ID: id, Name: strings.TrimPrefix(label, "@")}, id != ""
+	}
+
+	switch strings.TrimPrefix(target, "!") {
+	case "here", "channel", "everyone":
+		return model.Mention{Type: model.MentionTypeBroadcast, ID: strings.TrimPrefix(target, "!")}, true
+	}
+	return model.Mention{}, false
+}
@@ -0,0 +1,50 @@
+package mrkdwn
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/longkey1/slago/internal/model"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []model.Mention
+	}{
+		{
+			name:  "users with and without labels",
+			input: "<@U123> and <@U456|bob>, again <@U123|alice>",
+			want: []model.Mention{
+				{Type: model.MentionTypeUser, ID: "U123"},
+				{Type: model.MentionTypeUser, ID: "U456", Name: "bob"},
+			},
+		},
+		{
+			name:  "groups, broadcasts and channels",
+			input: "<!subteam^S1|@backend> <!subteam^S2> <!here> <!channel|channel> <#C1|general> <#C2>",
+			want: []model.Mention{
+				{Type: model.MentionTypeUserGroup, ID: "S1", Name: "backend"},
+				{Type: model.MentionTypeUserGroup, ID: "S2"},
+				{Type: model.MentionTypeBroadcast, ID: "here"},
+				{Type: model.MentionTypeBroadcast, ID: "channel"},
+				{Type: model.MentionTypeChannel, ID: "C1", Name: "general"},
+				{Type: model.MentionTypeChannel, ID: "C2"},
+			},
+		},
+		{
+			name:  "links and dates are not mentions",
+			input: "<https://example.com|@U123> <!date^1736935200^{date}|Jan 15>",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mentions(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mentions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+// Package mrkdwn converts Slack message markup into CommonMark and plain text
+package mrkdwn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Lookup resolves user and channel IDs to display names
+type Lookup interface {
+	UserName(userID string) (string, bool)
+	ChannelName(channelID string) (string, bool)
+}
+
+// NoLookup leaves IDs unresolved, using the labels embedded in the markup
+type NoLookup struct{}
+
+// UserName implements Lookup
+func (NoLookup) UserName(string) (string, bool) { return "", false }
+
+// ChannelName implements Lookup
+func (NoLookup) ChannelName(string) (string, bool) { return "", false }
+
+type format int
+
+const (
+	formatMarkdown format = iota
+	formatText
+)
+
+var (
+	codeBlockPattern   = regexp.MustCompile("(?s)```(.*?)```")
+	codeSpanPattern    = regexp.MustCompile("`([^`\n]+)`")
+	angleTokenPattern  = regexp.MustCompile(`<([^<>\n]+)>`)
+	placeholderPattern = regexp.MustCompile(`[\x{100000}-\x{10FFFD}]`)
+)
+
+var entityDecoder = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// markdownEscaper escapes characters Slack shows literally but CommonMark
+// would interpret
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`~`, `\~`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `\<`,
+)
+
+// Placeholders mark rendered tokens while the surrounding text is being
+// formatted. They are runes of the last private use plane; any such runes
+// in the text are tokens of their own. The plane has room for more tokens
+// than the 40,000 characters Slack allows in a message.
+const (
+	placeholderBase = '\U00100000'
+	placeholderLast = '\U0010FFFD'
+)
+
+// ToMarkdown converts Slack mrkdwn to CommonMark
+func ToMarkdown(text string, lookup Lookup) string {
+	return convert(text, lookup, formatMarkdown)
+}
+
+// ToText converts Slack mrkdwn to plain text without markup
+func ToText(text string, lookup Lookup) string {
+	return convert(text, lookup, formatText)
+}
+
+func convert(text string, lookup Lookup, f format) string {
+	if lookup == nil {
+		lookup = NoLookup{}
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range codeBlockPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(convertInline(text[last:loc[0]], lookup, f))
+		b.WriteString(codeBlock(text[loc[2]:loc[3]], f))
+		last = loc[1]
+	}
+	b.WriteString(convertInline(text[last:], lookup, f))
+	return b.String()
+}
+
+func codeBlock(code string, f format) string {
+	code = strings.Trim(decodeCode(code), "\n")
+	if f == formatText {
+		return code
+	}
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return "\n" + fence + "\n" + code + "\n" + fence + "\n"
+}
+
+// decodeCode turns the markup Slack keeps inside code back into what was typed
+func decodeCode(code string) string {
+	code = angleTokenPattern.ReplaceAllStringFunc(code, func(token string) string {
+		inner := token[1 : len(token)-1]
+		if _, label, ok := strings.Cut(inner, "|"); ok && label != "" {
+			return label
+		}
+		return inner
+	})
+	return entityDecoder.Replace(code)
+}
+
+func convertInline(text string, lookup Lookup, f format) string {
+	var tokens []string
+	keep := func(s string) string {
+		if placeholderBase+len(tokens) > placeholderLast {
+			return s
+		}
+		tokens = append(tokens, s)
+		return string(rune(placeholderBase + len(tokens) - 1))
+	}
+	// Tokens may hold the placeholders of earlier ones
+	placeholder := func(s string) string {
+		return keep(restore(s, tokens))
+	}
+
+	// Runes that would read as placeholders are set aside first
+	text = placeholderPattern.ReplaceAllStringFunc(text, keep)
+
+	text = codeSpanPattern.ReplaceAllStringFunc(text, func(span string) string {
+		code := decodeCode(span[1 : len(span)-1])
+		if f == formatText {
+			return placeholder(code)
+		}
+		return placeholder(codeSpan(code))
+	})
+	text = angleTokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		return placeholder(renderToken(token[1:len(token)-1], lookup, f))
+	})
+	text = entityDecoder.Replace(text)
+
+	text = formatSpans(text, f)
+
+	return restore(text, tokens)
+}
+
+func restore(text string, tokens []string) string {
+	if len(tokens) == 0 {
+		return text
+	}
+	var b strings.Builder
+	for _, r := range text {
+		if i := int(r - placeholderBase); i >= 0 && i < len(tokens) {
+			b.WriteString(tokens[i])
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// renderToken renders the inside of a <...> token: mentions, channels,
+// special mentions, dates and links
+func renderToken(inner string, lookup Lookup, f format) string {
+	target, label, _ := strings.Cut(inner, "|")
+
+	switch {
+	case strings.HasPrefix(target, "@"):
+		id := target[1:]
+		if name, ok := lookup.UserName(id); ok {
+			return "@" + escape(name, f)
+		}
+		if label != "" {
+			return "@" + escape(strings.TrimPrefix(label, "@"), f)
+		}
+		return "@" + id
+
+	case strings.HasPrefix(target, "#"):
+		id := target[1:]
+		if name, ok := lookup.ChannelName(id); ok {
+			return "#" + escape(name, f)
+		}
+		if label != "" {
+			return "#" + escape(label, f)
+		}
+		return "#" + id
+
+	case strings.HasPrefix(target, "!subteam^"):
+		if label != "" {
+			return "@" + escape(strings.TrimPrefix(label, "@"), f)
+		}
+		return "@" + strings.TrimPrefix(target, "!subteam^")
+
+	case strings.HasPrefix(target, "!date^"):
+		if label != "" {
+			return escape(label, f)
+		}
+		return escape(target, f)
+
+	case strings.HasPrefix(target, "!"):
+		// <!here>, <!channel>, <!everyone>
+		name := strings.TrimPrefix(target, "!")
+		if label != "" {
+			name = strings.TrimPrefix(label, "@")
+		}
+		return "@" + escape(name, f)
+	}
+
+	target = entityDecoder.Replace(target)
+	label = entityDecoder.Replace(label)
+	return link(target, label, f)
+}
+
+func link(url, label string, f format) string {
+	display := label
+	if strings.HasPrefix(url, "mailto:") && (label == "" || label == strings.TrimPrefix(url, "mailto:")) {
+		display = strings.TrimPrefix(url, "mailto:")
+	}
+
+	if f == formatText {
+		if display == "" || display == url {
+			return url
+		}
+		if strings.HasPrefix(url, "mailto:") {
+			return display
+		}
+		return fmt.Sprintf("%s (%s)", display, url)
+	}
+
+	escapedURL := strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(url)
+	if display == "" || display == url {
+		if isURL(url) {
+			return "<" + escapedURL + ">"
+		}
+		return markdownEscaper.Replace(url)
+	}
+	return fmt.Sprintf("[%s](%s)", markdownEscaper.Replace(display), escapedURL)
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "mailto:")
+}
+
+func escape(s string, f format) string {
+	if f == formatText {
+		return s
+	}
+	return markdownEscaper.Replace(s)
+}
+
+// formatSpans converts Slack's *bold*, _italic_ and ~strike~ spans. Markers
+// that do not form a span are kept as literal characters.
+func formatSpans(text string, f format) string {
+	var b strings.Builder
+	runes := []rune(text)
+	lineStart := true
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if marker, ok := spanMarker(r); ok {
+			if end := closingMarker(runes, i); end > 0 {
+				inner := formatSpans(string(runes[i+1:end]), f)
+				if f == formatMarkdown {
+					inner = marker + inner + marker
+				}
+				b.WriteString(inner)
+				i = end
+				lineStart = false
+				continue
+			}
+		}
+
+		switch {
+		case r == '\n':
+			lineStart = true
+			b.WriteRune(r)
+			continue
+		case f == formatMarkdown && lineStart && r == '#':
+			b.WriteString(`\#`)
+		case isPlaceholder(r):
+			b.WriteRune(r)
+		case f == formatMarkdown:
+			b.WriteString(markdownEscaper.Replace(string(r)))
+		default:
+			b.WriteRune(r)
+		}
+		if !unicode.IsSpace(r) {
+			lineStart = false
+		}
+	}
+	return b.String()
+}
+
+func spanMarker(r rune) (string, bool) {
+	switch r {
+	case '*':
+		return "**", true
+	case '_':
+		return "_", true
+	case '~':
+		return "~~", true
+	}
+	return "", false
+}
+
+// closingMarker finds the end of a span opened at runes[open], or -1. Like
+// Slack, spans start after a word boundary, do not touch whitespace on the
+// inside and stay on one line.
+func closingMarker(runes []rune, open int) int {
+	r := runes[open]
+	if open > 0 && isWordRune(runes[open-1]) {
+		return -1
+	}
+	if open+1 >= len(runes) || unicode.IsSpace(runes[open+1]) || runes[open+1] == r {
+		return -1
+	}
+
+	for j := open + 2; j < len(runes); j++ {
+		if runes[j] == '\n' {
+			return -1
+		}
+		if runes[j] != r || unicode.IsSpace(runes[j-1]) {
+			continue
+		}
+		if j+1 < len(runes) && isWordRune(runes[j+1]) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || isPlaceholder(r)
+}
+
+func isPlaceholder(r rune) bool {
+	return r >= placeholderBase && r <= placeholderLast
+}
+
+func codeSpan(code string) string {
+	fence := "`"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		return fence + " " + code + " " + fence
+	}
+	return fence + code + fence
+}
@@ -0,0 +1,100 @@
+package mrkdwn
+
+import "testing"
+
+type fakeLookup struct{}
+
+func (fakeLookup) UserName(id string) (string, bool) {
+	if id == "U123" {
+		return "alice", true
+	}
+	return "", false
+}
+
+func (fakeLookup) ChannelName(id string) (string, bool) {
+	if id == "C123" {
+		return "general", true
+	}
+	return "", false
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantMarkdown string
+		wantText     string
+	}{
+		{
+			name:         "user mentions",
+			input:        "hi <@U123> and <@U999|bob> and <@U888>",
+			wantMarkdown: "hi @alice and @bob and @U888",
+			wantText:     "hi @alice and @bob and @U888",
+		},
+		{
+			name:         "channels and special mentions",
+			input:        "<!here> see <#C123> and <#C999|random>, cc <!subteam^S1|@backend>",
+			wantMarkdown: "@here see #general and #random, cc @backend",
+			wantText:     "@here see #general and #random, cc @backend",
+		},
+		{
+			name:         "links",
+			input:        "read <https://example.com/a|the docs>, <https://example.com> or <mailto:a@example.com|a@example.com>",
+			wantMarkdown: "read [the docs](https://example.com/a), <https://example.com> or [a@example.com](mailto:a@example.com)",
+			wantText:     "read the docs (https://example.com/a), https://example.com or a@example.com",
+		},
+		{
+			name:         "entities",
+			input:        "a &lt; b &amp;&amp; c &gt; d",
+			wantMarkdown: `a \< b && c > d`,
+			wantText:     "a < b && c > d",
+		},
+		{
+			name:         "styles",
+			input:        "*bold* _italic_ ~strike~ *_both_*",
+			wantMarkdown: "**bold** _italic_ ~~strike~~ **_both_**",
+			wantText:     "bold italic strike both",
+		},
+		{
+			name:         "literal markers",
+			input:        "2*3*4 snake_case_name * not bold *",
+			wantMarkdown: `2\*3\*4 snake\_case\_name \* not bold \*`,
+			wantText:     "2*3*4 snake_case_name * not bold *",
+		},
+		{
+			name:         "code keeps markup literal",
+			input:        "run `make *all*` then\n```\nif a &lt; b {\n  *x*\n}\n```",
+			wantMarkdown: "run `make *all*` then\n\n```\nif a < b {\n  *x*\n}\n```\n",
+			wantText:     "run make *all* then\nif a < b {\n  *x*\n}",
+		},
+		{
+			name:         "quote and heading-like text",
+			input:        "&gt; quoted\n# not a heading",
+			wantMarkdown: "> quoted\n\\# not a heading",
+			wantText:     "> quoted\n# not a heading",
+		},
+		{
+			name:         "private use runes in the text",
+			input:        "\uE000 and \U00100000 for <@U123> in `a\U00100000`",
+			wantMarkdown: "\uE000 and \U00100000 for @alice in `a\U00100000`",
+			wantText:     "\uE000 and \U00100000 for @alice in a\U00100000",
+		},
+		{
+			name:         "code span inside a link label",
+			input:        "<https://example.com|run `make`>",
+			wantMarkdown: "[run `make`](https://example.com)",
+			wantText:     "run make (https://example.com)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToMarkdown(tt.input, fakeLookup{}); got != tt.wantMarkdown {
+				t.Errorf("ToMarkdown() = %q, want %q", got, tt.wantMarkdown)
+			}
+			if got := ToText(tt.input, fakeLookup{}); got != tt.wantText {
+				t.Errorf("ToText() = %q, want %q", got, tt.wantText)
+			}
+		})
+	}
+}
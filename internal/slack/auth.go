@@ -0,0 +1,161 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Token types, by token prefix
+const (
+	TokenTypeUser    = "user"
+	TokenTypeBot     = "bot"
+	TokenTypeApp     = "app"
+	TokenTypeUnknown = "unknown"
+)
+
+// TokenType tells user (xoxp-), bot (xoxb-) and app-level (xapp-) tokens
+// apart. Rotating tokens (xoxe.xoxp-, xoxe.xoxb-) report their underlying
+// type with rotating set.
+func TokenType(token string) (tokenType string, rotating bool) {
+	if rest, ok := strings.CutPrefix(token, "xoxe."); ok {
+		token = rest
+		rotating = true
+	}
+	switch {
+	case strings.HasPrefix(token, "xoxp-"):
+		return TokenTypeUser, rotating
+	case strings.HasPrefix(token, "xoxb-"):
+		return TokenTypeBot, rotating
+	case strings.HasPrefix(token, "xapp-"):
+		return TokenTypeApp, rotating
+	}
+	return TokenTypeUnknown, rotating
+}
+
+// AuthInfo describes the identity behind the API token
+type AuthInfo struct {
+	URL    string
+	Team   string
+	TeamID string
+	User   string
+	UserID string
+	BotID  string
+}
+
+// AuthTest gets the identity behind the API token, caching the result
+func (c *Client) AuthTest() (*AuthInfo, error) {
+	c.mu.Lock()
+	cached := c.auth
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var resp *slack.AuthTestResponse
+	err := c.call(Tier4, func() error {
+		var err error
+		resp, err = c.api.AuthTest()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("auth.test API error: %w", err)
+	}
+
+	info := &AuthInfo{
+		URL:    resp.URL,
+		Team:   resp.Team,
+		TeamID: resp.TeamID,
+		User:   resp.User,
+		UserID: resp.UserID,
+		BotID:  resp.BotID,
+	}
+
+	c.mu.Lock()
+	c.auth = info
+	c.mu.Unlock()
+
+	return info, nil
+}
+
+// Scopes lists the OAuth scopes granted to the token. slack-go drops the
+// x-oauth-scopes header of auth.test, so the method is called directly.
+func (c *Client) Scopes() ([]string, error) {
+	endpoint := c.apiURL
+	if endpoint == "" {
+		endpoint = slack.APIURL
+	}
+
+	var header string
+	err := c.call(Tier4, func() error {
+		req, err := http.NewRequest(http.MethodPost, endpoint+"auth.test", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+			return &slack.RateLimitedError{RetryAfter: time.Duration(retryAfter) * time.Second}
+		}
+
+		var body slack.SlackResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return fmt.Errorf("unexpected response (HTTP %d)", resp.StatusCode)
+		}
+		if err := body.Err(); err != nil {
+			return err
+		}
+		header = resp.Header.Get("X-OAuth-Scopes")
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("auth.test API error: %w", err)
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// Timezone returns the Slack timezone of the token's user, from users.info.
+// The directory cache is bypassed because it does not keep timezones.
+func (c *Client) Timezone() (*time.Location, error) {
+	auth, err := c.AuthTest()
+	if err != nil {
+		return nil, err
+	}
+	if auth.UserID == "" {
+		return nil, fmt.Errorf("token has no user identity")
+	}
+
+	var user *slack.User
+	err = c.call(Tier4, func() error {
+		var err error
+		user, err = c.api.GetUserInfo(auth.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("users.info API error: %w", err)
+	}
+	if user.TZ == "" {
+		return nil, fmt.Errorf("user %s has no timezone", auth.UserID)
+	}
+
+	return time.LoadLocation(user.TZ)
+}
@@ -0,0 +1,47 @@
+package slack_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/longkey1/slago/internal/slack"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+)
+
+func TestTokenType(t *testing.T) {
+	tests := []struct {
+		token        string
+		wantType     string
+		wantRotating bool
+	}{
+		{"xoxp-1-abc", slack.TokenTypeUser, false},
+		{"xoxb-1-abc", slack.TokenTypeBot, false},
+		{"xoxe.xoxp-1-abc", slack.TokenTypeUser, true},
+		{"xoxe.xoxb-1-abc", slack.TokenTypeBot, true},
+		{"xapp-1-abc", slack.TokenTypeApp, false},
+		{"abc", slack.TokenTypeUnknown, false},
+	}
+
+	for _, tt := range tests {
+		gotType, gotRotating := slack.TokenType(tt.token)
+		if gotType != tt.wantType || gotRotating != tt.wantRotating {
+			t.Errorf("TokenType(%q) = %q, %v, want %q, %v", tt.token, gotType, gotRotating, tt.wantType, tt.wantRotating)
+		}
+	}
+}
+
+func TestScopes(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.SetScopes("search:read", "channels:history", "users:read")
+
+	got, err := srv.Client().Scopes()
+	if err != nil {
+		t.Fatalf("Scopes() error = %v", err)
+	}
+	want := []string{"search:read", "channels:history", "users:read"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Scopes() = %v, want %v", got, want)
+	}
+}
@@ -3,13 +3,19 @@ package slack
 import (
 	"fmt"
 
+	"github.com/longkey1/slago/internal/model"
 	"github.com/slack-go/slack"
 )
 
 // GetChannelInfo gets information about a channel
 func (c *Client) GetChannelInfo(channelID string) (*slack.Channel, error) {
-	channel, err := c.api.GetConversationInfo(&slack.GetConversationInfoInput{
-		ChannelID: channelID,
+	var channel *slack.Channel
+	err := c.call(Tier3, func() error {
+		var err error
+		channel, err = c.api.GetConversationInfo(&slack.GetConversationInfoInput{
+			ChannelID: channelID,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("conversations.info API error: %w", err)
@@ -17,11 +23,126 @@ func (c *Client) GetChannelInfo(channelID string) (*slack.Channel, error) {
 	return channel, nil
 }
 
+// GetChannel gets a channel, reading through the directory cache
+func (c *Client) GetChannel(channelID string) (*model.Channel, error) {
+	if c.directory != nil {
+		if ch, ok := c.directory.Channel(channelID); ok {
+			return ch, nil
+		}
+	}
+
+	info, err := c.GetChannelInfo(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := convertChannel(*info)
+	if c.directory != nil {
+		c.directory.PutChannel(ch)
+	}
+	return &ch, nil
+}
+
 // GetChannelName gets the name of a channel, falling back to ID if not accessible
 func (c *Client) GetChannelName(channelID string) string {
-	channel, err := c.GetChannelInfo(channelID)
+	channel, err := c.GetChannel(channelID)
 	if err != nil {
 		return channelID
 	}
 	return channel.Name
 }
+
+// GetChannels lists the channels visible to the token and fills the directory cache.
+// The list is fetched once per client.
+func (c *Client) GetChannels() ([]model.Channel, error) {
+	c.mu.Lock()
+	cached := c.channels
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	channels := []model.Channel{}
+	params := &slack.GetConversationsParameters{
+		Types:           []string{"public_channel", "private_channel"},
+		ExcludeArchived: false,
+		Limit:           200,
+	}
+
+	for {
+		var page []slack.Channel
+		var nextCursor string
+		err := c.call(Tier2, func() error {
+			var err error
+			page, nextCursor, err = c.api.GetConversations(params)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("conversations.list API error: %w", err)
+		}
+
+		for _, ch := range page {
+			channels = append(channels, convertChannel(ch))
+		}
+
+		if nextCursor == "" {
+			break
+		}
+		params.Cursor = nextCursor
+	}
+
+	if c.directory != nil {
+		for _, ch := range channels {
+			c.directory.PutChannel(ch)
+		}
+	}
+
+	c.mu.Lock()
+	c.channels = channels
+	c.mu.Unlock()
+
+	return channels, nil
+}
+
+// GetConversationMembers lists the members of a conversation
+func (c *Client) GetConversationMembers(channelID string) ([]string, error) {
+	var members []string
+	params := &slack.GetUsersInConversationParameters{
+		ChannelID: channelID,
+		Limit:     200,
+	}
+
+	for {
+		var page []string
+		var nextCursor string
+		err := c.call(Tier4, func() error {
+			var err error
+			page, nextCursor, err = c.api.GetUsersInConversation(params)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("conversations.members API error: %w", err)
+		}
+
+		members = append(members, page...)
+
+		if nextCursor == "" {
+			break
+		}
+		params.Cursor = nextCursor
+	}
+
+	return members, nil
+}
+
+func convertChannel(ch slack.Channel) model.Channel {
+	return model.Channel{
+		ID:        ch.ID,
+		Name:      ch.Name,
+		IsMember:  ch.IsMember,
+		IsPrivate: ch.IsPrivate,
+		IsIM:      ch.IsIM,
+		IsMPIM:    ch.IsMpIM,
+		User:      ch.User,
+	}
+}
@@ -0,0 +1,32 @@
+package slack_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/longkey1/slago/internal/cache"
+	slago "github.com/longkey1/slago/internal/slack"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+)
+
+func TestGetChannelNameReadsThroughDirectory(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+	srv.AddChannel("C1", "general")
+
+	dir, err := cache.Open(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("cache.Open() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		client := srv.Client(slago.WithDirectory(dir))
+		if got := client.GetChannelName("C1"); got != "general" {
+			t.Errorf("GetChannelName(C1) = %q, want %q", got, "general")
+		}
+	}
+
+	if calls := srv.Calls("conversations.info"); calls != 1 {
+		t.Errorf("conversations.info calls = %d, want 1", calls)
+	}
+}
@@ -1,19 +1,130 @@
 package slack
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
 	"github.com/slack-go/slack"
 )
 
+// Service is the set of Slack operations used by the collectors.
+// Client is the production implementation.
+type Service interface {
+	SearchMessages(opts SearchOptions) ([]model.Message, error)
+	GetThreadReplies(channelID, threadTS string) ([]model.Message, error)
+	GetThread(channelID, threadTS string) (*model.Thread, error)
+	GetChannel(channelID string) (*model.Channel, error)
+	GetChannelName(channelID string) string
+	GetConversationMembers(channelID string) ([]string, error)
+	GetPermalink(channelID, ts string) (string, error)
+	GetReactions(channelID, ts string) ([]model.Reaction, error)
+	GetMessage(channelID, ts string) (*model.Message, error)
+	DownloadFile(url string, w io.Writer) error
+	GetUser(userID string) (*model.User, error)
+	GetUsers() ([]model.User, error)
+	ResolveUser(ref string) (*model.User, error)
+	AuthTest() (*AuthInfo, error)
+	ResolveMentions(refs []string, includeMembers bool) []MentionFilter
+	GetChannels() ([]model.Channel, error)
+	GetChannelHistory(channelID string, oldest, latest time.Time) ([]model.Message, error)
+	Listen(ctx context.Context, appToken string, handle func(model.Message)) error
+}
+
+var _ Service = (*Client)(nil)
+
+// Directory caches channel and user lookups across runs
+type Directory interface {
+	Channel(channelID string) (*model.Channel, bool)
+	PutChannel(ch model.Channel)
+	User(userID string) (*model.User, bool)
+	PutUser(u model.User)
+}
+
+// requestTimeout bounds the Web API calls made without slack-go
+const requestTimeout = 30 * time.Second
+
 // Client wraps the Slack API client
 type Client struct {
-	api *slack.Client
+	api     *slack.Client
+	token   string
+	apiURL  string
+	limiter *RateLimiter
+	// httpClient makes the Web API calls slack-go does not cover
+	httpClient *http.Client
+	// searchPages is how many result pages search can fetch
+	searchPages int
+
+	directory Directory
+
+	mu         sync.Mutex
+	auth       *AuthInfo
+	users      map[string]userEntry
+	channels   []model.Channel
+	userGroups []UserGroup
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIURL points the client at a different Web API endpoint (used for testing)
+func WithAPIURL(url string) Option {
+	return func(c *Client) {
+		c.apiURL = url
+	}
+}
+
+// WithRateLimiter replaces the default tier-based rate limiter
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithSearchPageLimit lowers how many search result pages can be fetched
+// before a search window is split (used for testing)
+func WithSearchPageLimit(pages int) Option {
+	return func(c *Client) {
+		c.searchPages = pages
+	}
+}
+
+// WithDirectory reads channel and user lookups through a directory cache
+func WithDirectory(d Directory) Option {
+	return func(c *Client) {
+		c.directory = d
+	}
 }
 
 // NewClient creates a new Slack client
-func NewClient(token string) *Client {
-	return &Client{
-		api: slack.New(token),
+func NewClient(token string, opts ...Option) *Client {
+	c := &Client{
+		token:       token,
+		httpClient:  &http.Client{Timeout: requestTimeout},
+		limiter:     NewRateLimiter(DefaultLimits),
+		searchPages: maxSearchPages,
+		users:       make(map[string]userEntry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var apiOpts []slack.Option
+	if c.apiURL != "" {
+		apiOpts = append(apiOpts, slack.OptionAPIURL(c.apiURL))
 	}
+	c.api = slack.New(token, apiOpts...)
+
+	return c
+}
+
+// SetDirectory sets the directory cache after construction, once the
+// workspace it belongs to is known
+func (c *Client) SetDirectory(d Directory) {
+	c.directory = d
 }
 
 // API returns the underlying Slack API client
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/slack-go/slack"
+)
+
+// GetMessage fetches a single message with everything conversations.replies
+// returns for it, such as files and reactions, which search results lack
+func (c *Client) GetMessage(channelID, ts string) (*model.Message, error) {
+	params := &slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: ts,
+		Latest:    ts,
+		Inclusive: true,
+		Limit:     1,
+	}
+
+	var msgs []slack.Message
+	err := c.call(Tier3, func() error {
+		var err error
+		msgs, _, _, err = c.api.GetConversationReplies(params)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("conversations.replies API error: %w", err)
+	}
+
+	for _, msg := range msgs {
+		if msg.Timestamp == ts {
+			m := c.convertReplyMessage(msg, channelID, "")
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("message %s not found in %s", ts, channelID)
+}
+
+// DownloadFile writes the content of a file's url_private to w, authenticated
+// with the token. Downloads are not Web API methods and are not rate limited.
+func (c *Client) DownloadFile(url string, w io.Writer) error {
+	if err := c.api.GetFile(url, w); err != nil {
+		return fmt.Errorf("file download error: %w", err)
+	}
+	return nil
+}
+
+func convertFiles(files []slack.File) []model.File {
+	if len(files) == 0 {
+		return nil
+	}
+	result := make([]model.File, 0, len(files))
+	for _, f := range files {
+		result = append(result, model.File{
+			ID:         f.ID,
+			Name:       f.Name,
+			Title:      f.Title,
+			Mimetype:   f.Mimetype,
+			Size:       int64(f.Size),
+			URLPrivate: f.URLPrivate,
+			Permalink:  f.Permalink,
+		})
+	}
+	return result
+}
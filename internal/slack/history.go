@@ -0,0 +1,49 @@
+package slack
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/slack-go/slack"
+)
+
+// GetChannelHistory fetches the top-level messages posted in a channel in
+// [oldest, latest). Unlike search it works with bot tokens.
+func (c *Client) GetChannelHistory(channelID string, oldest, latest time.Time) ([]model.Message, error) {
+	var allMessages []model.Message
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Oldest:    model.TSFromTime(oldest).String(),
+		Latest:    model.TSFromTime(latest).String(),
+		Inclusive: true,
+		Limit:     200,
+	}
+
+	for {
+		var resp *slack.GetConversationHistoryResponse
+		err := c.call(Tier3, func() error {
+			var err error
+			resp, err = c.api.GetConversationHistory(params)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("conversations.history API error: %w", err)
+		}
+
+		for _, msg := range resp.Messages {
+			m := c.convertReplyMessage(msg, channelID, "")
+			if !m.TS().Before(model.TSFromTime(latest)) {
+				continue
+			}
+			allMessages = append(allMessages, m)
+		}
+
+		if !resp.HasMore || resp.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = resp.ResponseMetaData.NextCursor
+	}
+
+	return allMessages, nil
+}
@@ -0,0 +1,59 @@
+package slack
+
+import (
+	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/mrkdwn"
+	"github.com/slack-go/slack"
+)
+
+// extractLinks collects the links of a message text and its attachments.
+// Unfurls of links in the text add their title and description to the text
+// link instead of being listed twice.
+func extractLinks(text string, attachments []slack.Attachment) []model.Link {
+	links := mrkdwn.Links(text)
+	index := make(map[string]int, len(links))
+	for i, link := range links {
+		index[link.URL] = i
+	}
+
+	for _, att := range attachments {
+		link := attachmentLink(att)
+		if link.URL == "" {
+			continue
+		}
+		if i, ok := index[link.URL]; ok {
+			if links[i].Title == "" {
+				links[i].Title = link.Title
+			}
+			if links[i].Text == "" {
+				links[i].Text = link.Text
+			}
+			continue
+		}
+		index[link.URL] = len(links)
+		links = append(links, link)
+	}
+	return links
+}
+
+// attachmentLink describes an attachment. Unfurls carry the unfurled URL in
+// from_url or original_url; other attachments link their title.
+func attachmentLink(att slack.Attachment) model.Link {
+	link := model.Link{
+		Title: att.Title,
+		Text:  att.Text,
+	}
+	switch {
+	case att.FromURL != "":
+		link.URL = att.FromURL
+		link.Source = model.LinkSourceUnfurl
+	case att.OriginalURL != "":
+		link.URL = att.OriginalURL
+		link.Source = model.LinkSourceUnfurl
+	default:
+		link.URL = att.TitleLink
+		link.Source = model.LinkSourceAttachment
+	}
+	link.Domain = mrkdwn.Domain(link.URL)
+	return link
+}
@@ -0,0 +1,39 @@
+package slack
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/slack-go/slack"
+)
+
+func TestExtractLinks(t *testing.T) {
+	attachments := []slack.Attachment{
+		{FromURL: "https://github.com/o/r/pull/1", Title: "Fix the thing", Text: "This PR fixes the thing"},
+		{TitleLink: "https://status.example.com/incidents/9", Title: "Incident 9", Text: "Resolved"},
+		{Text: "no link"},
+	}
+
+	got := extractLinks("review <https://github.com/o/r/pull/1|PR #1>", attachments)
+	want := []model.Link{
+		{
+			URL:    "https://github.com/o/r/pull/1",
+			Label:  "PR #1",
+			Domain: "github.com",
+			Source: model.LinkSourceText,
+			Title:  "Fix the thing",
+			Text:   "This PR fixes the thing",
+		},
+		{
+			URL:    "https://status.example.com/incidents/9",
+			Domain: "status.example.com",
+			Source: model.LinkSourceAttachment,
+			Title:  "Incident 9",
+			Text:   "Resolved",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractLinks() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package slack
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const maxRetries = 5
+
+// Tier is a Slack Web API rate limit tier
+type Tier int
+
+// Slack Web API rate limit tiers
+const (
+	Tier1 Tier = iota + 1
+	Tier2
+	Tier3
+	Tier4
+)
+
+// Limit describes the request budget of a tier
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+// DefaultLimits follows the documented Slack tier limits
+var DefaultLimits = map[Tier]Limit{
+	Tier1: {PerMinute: 1, Burst: 1},
+	Tier2: {PerMinute: 20, Burst: 3},
+	Tier3: {PerMinute: 50, Burst: 5},
+	Tier4: {PerMinute: 100, Burst: 10},
+}
+
+// RateLimiter is a set of token buckets, one per tier, shared by every
+// worker using the same client. A rate limited response pauses all tiers.
+type RateLimiter struct {
+	mu          sync.Mutex
+	buckets     map[Tier]*bucket
+	pausedUntil time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+type bucket struct {
+	tokens   float64
+	capacity float64
+	perSec   float64
+	last     time.Time
+}
+
+// NewRateLimiter creates a rate limiter with the given per-tier limits.
+// Tiers without a limit are not throttled.
+func NewRateLimiter(limits map[Tier]Limit) *RateLimiter {
+	l := &RateLimiter{
+		buckets: make(map[Tier]*bucket),
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+	for tier, limit := range limits {
+		if limit.PerMinute <= 0 {
+			continue
+		}
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		l.buckets[tier] = &bucket{
+			tokens:   float64(burst),
+			capacity: float64(burst),
+			perSec:   float64(limit.PerMinute) / 60,
+		}
+	}
+	return l
+}
+
+// Wait blocks until a request of the given tier may be sent
+func (l *RateLimiter) Wait(tier Tier) {
+	if d := l.reserve(tier); d > 0 {
+		l.sleep(d)
+	}
+}
+
+// Pause holds back every request until d has elapsed
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := l.now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// reserve takes a token from the tier's bucket and returns how long the
+// caller has to wait before using it
+func (l *RateLimiter) reserve(tier Tier) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	if l.pausedUntil.After(now) {
+		wait = l.pausedUntil.Sub(now)
+	}
+
+	b, ok := l.buckets[tier]
+	if !ok {
+		return wait
+	}
+
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.perSec
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+	b.tokens--
+
+	if b.tokens < 0 {
+		refill := time.Duration(-b.tokens / b.perSec * float64(time.Second))
+		if refill > wait {
+			wait = refill
+		}
+	}
+	return wait
+}
+
+// call runs fn under the rate limiter, retrying with backoff when Slack
+// answers with a rate limited error
+func (c *Client) call(tier Tier, fn func() error) error {
+	var err error
+	for retry := 0; retry < maxRetries; retry++ {
+		c.limiter.Wait(tier)
+
+		err = fn()
+		var rateLimitErr *slack.RateLimitedError
+		if !errors.As(err, &rateLimitErr) {
+			return err
+		}
+
+		waitTime := rateLimitErr.RetryAfter
+		if waitTime == 0 {
+			waitTime = time.Duration(1<<retry) * time.Second
+		}
+		c.limiter.Pause(waitTime)
+	}
+	return err
+}
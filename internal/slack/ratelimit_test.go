@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func (f *fakeClock) Sleep(d time.Duration) {
+	f.slept += d
+	f.now = f.now.Add(d)
+}
+
+func newTestRateLimiter(limits map[Tier]Limit) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)}
+	l := NewRateLimiter(limits)
+	l.now = clock.Now
+	l.sleep = clock.Sleep
+	return l, clock
+}
+
+func TestRateLimiterBurstThenRefill(t *testing.T) {
+	l, clock := newTestRateLimiter(map[Tier]Limit{
+		Tier2: {PerMinute: 20, Burst: 3},
+	})
+
+	for i := 0; i < 3; i++ {
+		l.Wait(Tier2)
+	}
+	if clock.slept != 0 {
+		t.Fatalf("burst requests slept %v, want 0", clock.slept)
+	}
+
+	l.Wait(Tier2)
+	if clock.slept != 3*time.Second {
+		t.Errorf("fourth request slept %v, want %v", clock.slept, 3*time.Second)
+	}
+}
+
+func TestRateLimiterTiersAreIndependent(t *testing.T) {
+	l, clock := newTestRateLimiter(map[Tier]Limit{
+		Tier2: {PerMinute: 20, Burst: 1},
+		Tier3: {PerMinute: 50, Burst: 1},
+	})
+
+	l.Wait(Tier2)
+	l.Wait(Tier3)
+	if clock.slept != 0 {
+		t.Errorf("requests on different tiers slept %v, want 0", clock.slept)
+	}
+}
+
+func TestRateLimiterPauseAffectsAllTiers(t *testing.T) {
+	l, clock := newTestRateLimiter(nil)
+
+	l.Pause(30 * time.Second)
+	l.Wait(Tier3)
+	if clock.slept != 30*time.Second {
+		t.Errorf("Wait() after Pause slept %v, want %v", clock.slept, 30*time.Second)
+	}
+
+	l.Pause(10 * time.Second)
+	l.Pause(5 * time.Second)
+	l.Wait(Tier2)
+	if clock.slept != 40*time.Second {
+		t.Errorf("shorter Pause shortened the wait: slept %v, want %v", clock.slept, 40*time.Second)
+	}
+}
@@ -0,0 +1,38 @@
+package slack
+
+import (
+	"fmt"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/slack-go/slack"
+)
+
+// GetReactions fetches the reactions on a message. Search results do not
+// carry reactions, so matches found by search need this extra call.
+func (c *Client) GetReactions(channelID, ts string) ([]model.Reaction, error) {
+	var reactions []slack.ItemReaction
+	err := c.call(Tier3, func() error {
+		var err error
+		reactions, err = c.api.GetReactions(slack.NewRefToMessage(channelID, ts), slack.GetReactionsParameters{Full: true})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reactions.get API error: %w", err)
+	}
+	return convertReactions(reactions), nil
+}
+
+func convertReactions(reactions []slack.ItemReaction) []model.Reaction {
+	if len(reactions) == 0 {
+		return nil
+	}
+	result := make([]model.Reaction, 0, len(reactions))
+	for _, r := range reactions {
+		result = append(result, model.Reaction{
+			Name:  r.Name,
+			Count: r.Count,
+			Users: r.Users,
+		})
+	}
+	return result
+}
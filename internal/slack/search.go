@@ -1,149 +1,357 @@
 package slack
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/mrkdwn"
 	"github.com/slack-go/slack"
 )
 
 // SearchOptions contains options for searching messages
 type SearchOptions struct {
 	Author          string
-	Mentions        []string
+	Mentions        []MentionFilter
 	Channels        []string
 	ExcludeChannels []string
 	After           time.Time
 	Before          time.Time
+	IncludeDMs      bool
+	IncludeMPDMs    bool
+	// Reactions are required to be on every match, by emoji name
+	Reactions []string
+	// WithReactions fetches reactions of matches outside threads, which
+	// search results do not include
+	WithReactions bool
+	// WithFiles fetches the shared files of matches outside threads
+	WithFiles bool
+	// WithSubtypes fetches the subtype and bot ID of matches outside
+	// threads, which search results do not include
+	WithSubtypes bool
+	// Keywords must all appear; keywords with spaces are searched as phrases
+	Keywords []string
+	// Has adds has: modifiers such as link, pin or reaction
+	Has []string
+	// Query holds extra terms in Slack's search syntax, passed through as is
+	Query string
+	// From and To, when set, keep only matches posted in [From, To). After
+	// and Before should cover the range.
+	From time.Time
+	To   time.Time
 }
 
-const searchMaxRetries = 5
-
 // SearchMessages searches for messages matching the given options
 func (c *Client) SearchMessages(opts SearchOptions) ([]model.Message, error) {
 	var allMessages []model.Message
 	processedThreads := make(map[string]bool)
 
-	query := c.buildSearchQuery(opts)
+	// Mention filters with alternative terms need one query per combination
+	window := windowOf(opts)
+	for _, base := range baseQueries(opts) {
+		messages, _, err := c.searchWindow(base, window, nil, processedThreads, opts)
+		if err != nil {
+			return nil, err
+		}
+		allMessages = append(allMessages, messages...)
+	}
+
+	return c.deduplicateMessages(allMessages), nil
+}
+
+// splitPageFactor bounds the search.messages calls a split may make, as a
+// multiple of the pages the unsplit query would need to fetch every match.
+// Splitting a window that search does not narrow never ends up under the cap,
+// so without a bound it would cost every hour of the window in calls.
+const splitPageFactor = 4
+
+// errSplitBudget stops a split that used up its calls
+var errSplitBudget = errors.New("split search call budget exhausted")
+
+// searchWindow runs a query over a time window. Slack only pages so deep
+// into results, so when the matches reach the cap the window is split in
+// halves, down to an hour, and each half is searched on its own. The halves
+// share a budget of calls; nil means no split is running. It returns the
+// messages and the number of matches they came from.
+func (c *Client) searchWindow(base string, w searchWindow, budget *int, processedThreads map[string]bool, opts SearchOptions) ([]model.Message, int, error) {
+	query := w.query(base)
 	params := slack.SearchParameters{
 		Count: 100,
 		Sort:  "timestamp",
 	}
+	limit := c.searchPages * params.Count
 
+	var matches []slack.SearchMessage
 	for {
+		if budget != nil {
+			if *budget <= 0 {
+				return nil, 0, errSplitBudget
+			}
+			*budget--
+		}
 		var result *slack.SearchMessages
-		var err error
-
-		// Retry with exponential backoff for rate limits
-		for retry := 0; retry < searchMaxRetries; retry++ {
+		err := c.call(Tier2, func() error {
+			var err error
 			result, err = c.api.SearchMessages(query, params)
-			if err == nil {
-				break
-			}
+			return err
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("search.messages API error: %w", err)
+		}
 
-			if rateLimitErr, ok := err.(*slack.RateLimitedError); ok {
-				waitTime := rateLimitErr.RetryAfter
-				if waitTime == 0 {
-					waitTime = time.Duration(1<<retry) * time.Second
+		total := result.Paging.Total
+		if len(matches) == 0 && total > limit {
+			if halves, ok := w.split(); ok {
+				split := budget
+				if split == nil {
+					pages := splitPageFactor * ((total + params.Count - 1) / params.Count)
+					split = &pages
+				}
+				// The halves mark threads in a copy, so that the unsplit
+				// query can still expand them if the halves are dropped
+				threads := maps.Clone(processedThreads)
+				messages, count, err := c.searchHalves(base, halves, split, threads, opts)
+				switch {
+				case errors.Is(err, errSplitBudget) && budget == nil:
+					fmt.Printf("[WARN] Split search of %q ran out of calls: keeping the first %d of %d matches of the unsplit query\n",
+						query, limit, total)
+				case err != nil:
+					return nil, 0, err
+				// Hour-precision after:/before: terms are not documented by
+				// Slack. If the halves find less than the query itself can
+				// page through, they are not trusted.
+				case count >= limit:
+					maps.Copy(processedThreads, threads)
+					if budget == nil {
+						fmt.Printf("[INFO] Search hit the %d result cap (%d matches): split into hourly windows, recovered %d messages\n",
+							limit, total, count-limit)
+					}
+					return messages, count, nil
+				default:
+					fmt.Printf("[WARN] Split search of %q found %d matches, fewer than the first %d of %d: keeping the unsplit results\n",
+						query, count, limit, total)
 				}
-				time.Sleep(waitTime)
-				continue
+			} else {
+				fmt.Printf("[WARN] Search %q has %d matches, only the first %d can be fetched\n", query, total, limit)
 			}
+		}
 
-			return nil, fmt.Errorf("search.messages API error: %w", err)
+		matches = append(matches, result.Matches...)
+
+		// Check for more pages
+		if len(result.Matches) == 0 || result.Paging.Pages <= result.Paging.Page || len(matches) >= limit {
+			break
 		}
+		params.Page = result.Paging.Page + 1
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("search.messages API error after retries: %w", err)
+	var kept []slack.SearchMessage
+	for _, match := range matches {
+		if w.contains(model.TS(match.Timestamp).Time()) {
+			kept = append(kept, match)
 		}
+	}
+	return c.convertMatches(kept, processedThreads, opts), len(kept), nil
+}
 
-		if len(result.Matches) == 0 {
-			break
+// searchHalves searches both halves of a split window
+func (c *Client) searchHalves(base string, halves [2]searchWindow, budget *int, processedThreads map[string]bool, opts SearchOptions) ([]model.Message, int, error) {
+	var messages []model.Message
+	count := 0
+	for _, half := range halves {
+		msgs, n, err := c.searchWindow(base, half, budget, processedThreads, opts)
+		if err != nil {
+			return nil, 0, err
 		}
+		messages = append(messages, msgs...)
+		count += n
+	}
+	return messages, count, nil
+}
+
+// convertMatches converts search matches, expanding those in threads into
+// their whole thread once
+func (c *Client) convertMatches(matches []slack.SearchMessage, processedThreads map[string]bool, opts SearchOptions) []model.Message {
+	var allMessages []model.Message
 
-		for _, match := range result.Matches {
-			msg := c.convertSearchMatch(match)
-
-			// Check if this is part of a thread
-			threadTS := c.extractThreadTS(match)
-			if threadTS != "" && threadTS != msg.ID {
-				// This message is in a thread
-				if !processedThreads[threadTS] {
-					// Get the entire thread
-					threadMsgs, err := c.GetThreadReplies(match.Channel.ID, threadTS)
-					if err != nil {
-						// Log error but continue
-						fmt.Printf("[WARN] Failed to get thread %s: %v\n", threadTS, err)
-						allMessages = append(allMessages, msg)
-					} else {
-						allMessages = append(allMessages, threadMsgs...)
+	for _, match := range matches {
+		msg := c.convertSearchMatch(match)
+
+		// Check if this is part of a thread
+		threadTS := c.extractThreadTS(match)
+		if threadTS != "" && threadTS != msg.ID {
+			// This message is in a thread; timestamps may repeat across
+			// channels
+			key := match.Channel.ID + "/" + threadTS
+			if !processedThreads[key] {
+				// Get the entire thread
+				threadMsgs, err := c.GetThreadReplies(match.Channel.ID, threadTS)
+				if err != nil {
+					// Log error but continue
+					fmt.Printf("[WARN] Failed to get thread %s: %v\n", threadTS, err)
+					allMessages = append(allMessages, msg)
+				} else {
+					for i := range threadMsgs {
+						threadMsgs[i].Channel = msg.Channel
+						threadMsgs[i].ChannelType = msg.ChannelType
+						threadMsgs[i].IsPrivate = msg.IsPrivate
 					}
-					processedThreads[threadTS] = true
+					allMessages = append(allMessages, threadMsgs...)
 				}
-			} else {
-				allMessages = append(allMessages, msg)
+				processedThreads[key] = true
 			}
+		} else {
+			c.fillDetails(&msg, opts)
+			allMessages = append(allMessages, msg)
 		}
+	}
 
-		// Check for more pages
-		if result.Paging.Pages <= result.Paging.Page {
-			break
-		}
-		params.Page = result.Paging.Page + 1
+	return allMessages
+}
 
-		// Rate limit prevention
-		time.Sleep(time.Second)
+// fillDetails fetches the reactions, files, subtype and bot ID of a search
+// match, which search results leave out
+func (c *Client) fillDetails(msg *model.Message, opts SearchOptions) {
+	withReactions := opts.WithReactions || len(opts.Reactions) > 0
+	switch {
+	case opts.WithFiles || opts.WithSubtypes:
+		// conversations.replies returns them all in one call
+		full, err := c.GetMessage(msg.ChannelID, msg.ID)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to get message %s: %v\n", msg.ID, err)
+			return
+		}
+		if opts.WithFiles {
+			msg.Files = full.Files
+		}
+		if withReactions {
+			msg.Reactions = full.Reactions
+		}
+		msg.Subtype = full.Subtype
+		msg.BotID = full.BotID
+	case withReactions:
+		reactions, err := c.GetReactions(msg.ChannelID, msg.ID)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to get reactions of %s: %v\n", msg.ID, err)
+		}
+		msg.Reactions = reactions
 	}
-
-	return c.deduplicateMessages(allMessages), nil
 }
 
-func (c *Client) buildSearchQuery(opts SearchOptions) string {
-	var parts []string
+// SearchQueries builds the search.messages queries for the options
+func SearchQueries(opts SearchOptions) []string {
+	window := windowOf(opts)
+	queries := baseQueries(opts)
+	for i := range queries {
+		queries[i] = window.query(queries[i])
+	}
+	return queries
+}
 
+// baseQueries builds the queries without their date bounds
+func baseQueries(opts SearchOptions) []string {
+	var prefix []string
 	if opts.Author != "" {
-		parts = append(parts, fmt.Sprintf("from:%s", opts.Author))
+		prefix = append(prefix, fmt.Sprintf("from:<@%s>", opts.Author))
 	}
 
-	for _, mention := range opts.Mentions {
-		// Handle both user IDs and group mentions
-		if strings.HasPrefix(mention, "@") || strings.HasPrefix(mention, "U") {
-			parts = append(parts, fmt.Sprintf("to:%s", mention))
-		} else {
-			parts = append(parts, fmt.Sprintf("@%s", mention))
+	var suffix []string
+	for _, keyword := range opts.Keywords {
+		if term := keywordTerm(keyword); term != "" {
+			suffix = append(suffix, term)
 		}
 	}
 
+	for _, has := range opts.Has {
+		if has = strings.TrimPrefix(strings.TrimSpace(has), "has:"); has != "" {
+			suffix = append(suffix, "has:"+has)
+		}
+	}
+
+	if query := strings.TrimSpace(opts.Query); query != "" {
+		suffix = append(suffix, query)
+	}
+
 	for _, channel := range opts.Channels {
-		parts = append(parts, fmt.Sprintf("in:%s", channel))
+		suffix = append(suffix, fmt.Sprintf("in:%s", channel))
 	}
 
 	for _, channel := range opts.ExcludeChannels {
-		parts = append(parts, fmt.Sprintf("-in:%s", channel))
+		suffix = append(suffix, fmt.Sprintf("-in:%s", channel))
+	}
+
+	for _, name := range opts.Reactions {
+		suffix = append(suffix, fmt.Sprintf("has::%s:", strings.Trim(name, ":")))
+	}
+
+	// Exclude DMs and group DMs unless asked for
+	if !opts.IncludeDMs {
+		suffix = append(suffix, "-is:dm")
+	}
+	if !opts.IncludeMPDMs {
+		suffix = append(suffix, "-is:mpdm")
 	}
 
-	if !opts.After.IsZero() {
-		parts = append(parts, fmt.Sprintf("after:%s", opts.After.Format("2006-01-02")))
+	// Every mention filter must match, each through any one of its terms.
+	// Filters with IDs are checked locally after the search, so only the
+	// one with the fewest terms is searched instead of every combination of
+	// their terms. Filters without IDs are all searched.
+	var searched []MentionFilter
+	var fewest *MentionFilter
+	for i, mention := range opts.Mentions {
+		if len(mention.IDs) == 0 {
+			searched = append(searched, mention)
+		} else if fewest == nil || len(mention.Terms) < len(fewest.Terms) {
+			fewest = &opts.Mentions[i]
+		}
+	}
+	if fewest != nil {
+		searched = append(searched, *fewest)
 	}
 
-	if !opts.Before.IsZero() {
-		parts = append(parts, fmt.Sprintf("before:%s", opts.Before.Format("2006-01-02")))
+	combos := [][]string{nil}
+	for _, mention := range searched {
+		var next [][]string
+		for _, combo := range combos {
+			for _, term := range mention.Terms {
+				next = append(next, append(append([]string(nil), combo...), term))
+			}
+		}
+		combos = next
 	}
 
-	// Exclude DMs and group DMs
-	parts = append(parts, "-is:dm", "-is:mpdm")
+	queries := make([]string, 0, len(combos))
+	for _, combo := range combos {
+		parts := append(append(append([]string(nil), prefix...), combo...), suffix...)
+		queries = append(queries, strings.Join(parts, " "))
+	}
+	return queries
+}
 
-	return strings.Join(parts, " ")
+// keywordTerm quotes keywords containing spaces so they match as a phrase
+func keywordTerm(keyword string) string {
+	keyword = strings.Trim(strings.TrimSpace(keyword), `"`)
+	if strings.ContainsAny(keyword, " \t") {
+		return `"` + keyword + `"`
+	}
+	return keyword
 }
 
 func (c *Client) convertSearchMatch(match slack.SearchMessage) model.Message {
-	ts := c.parseTimestamp(match.Timestamp)
+	ts := model.TS(match.Timestamp).Time()
+	channel := model.Channel{
+		ID:        match.Channel.ID,
+		Name:      match.Channel.Name,
+		IsPrivate: match.Channel.IsPrivate,
+		IsIM:      strings.HasPrefix(match.Channel.ID, "D"),
+		IsMPIM:    match.Channel.IsMPIM,
+	}
 
-	return model.Message{
+	msg := model.Message{
 		ID:             match.Timestamp,
 		Type:           "slack_message",
 		Content:        match.Text,
@@ -151,12 +359,21 @@ func (c *Client) convertSearchMatch(match slack.SearchMessage) model.Message {
 		Timestamp:      ts,
 		Channel:        match.Channel.Name,
 		ChannelID:      match.Channel.ID,
+		ChannelType:    channel.Type(),
+		IsPrivate:      channel.Type() != model.ChannelTypePublic,
 		Permalink:      match.Permalink,
-		Mentions:       c.extractMentions(match.Text),
-		AttachedLinks:  c.extractLinks(match),
+		Mentions:       mrkdwn.Mentions(match.Text),
+		AttachedLinks:  extractLinks(match.Text, match.Attachments),
 		ThreadTS:       match.Timestamp,
 		IsThreadParent: true,
+		Blocks:         rawBlocks(match.Blocks),
 	}
+
+	// Search results carry no bot ID; bot posts have a username but no user
+	if match.User == "" && match.Username != "" {
+		msg.BotName = match.Username
+	}
+	return msg
 }
 
 func (c *Client) extractThreadTS(match slack.SearchMessage) string {
@@ -171,65 +388,14 @@ func (c *Client) extractThreadTS(match slack.SearchMessage) string {
 	return ""
 }
 
-func (c *Client) parseTimestamp(ts string) time.Time {
-	parts := strings.Split(ts, ".")
-	if len(parts) == 0 {
-		return time.Time{}
-	}
-	sec, err := strconv.ParseInt(parts[0], 10, 64)
-	if err != nil {
-		return time.Time{}
-	}
-	return time.Unix(sec, 0)
-}
-
-func (c *Client) extractMentions(text string) []string {
-	re := regexp.MustCompile(`<@([^|>]+)\|([^>]+)>`)
-	matches := re.FindAllStringSubmatch(text, -1)
-
-	seen := make(map[string]bool)
-	var mentions []string
-	for _, match := range matches {
-		if len(match) > 2 && !seen[match[2]] {
-			mentions = append(mentions, match[2])
-			seen[match[2]] = true
-		}
-	}
-	return mentions
-}
-
-func (c *Client) extractLinks(match slack.SearchMessage) []string {
-	seen := make(map[string]bool)
-	var links []string
-
-	// Extract from text
-	re := regexp.MustCompile(`https?://[^\s>]+`)
-	textLinks := re.FindAllString(match.Text, -1)
-	for _, link := range textLinks {
-		if !seen[link] {
-			links = append(links, link)
-			seen[link] = true
-		}
-	}
-
-	// Extract from attachments
-	for _, att := range match.Attachments {
-		if att.TitleLink != "" && !seen[att.TitleLink] {
-			links = append(links, att.TitleLink)
-			seen[att.TitleLink] = true
-		}
-	}
-
-	return links
-}
-
 func (c *Client) deduplicateMessages(messages []model.Message) []model.Message {
 	seen := make(map[string]bool)
 	var result []model.Message
 
 	for _, msg := range messages {
-		if !seen[msg.ID] {
-			seen[msg.ID] = true
+		key := msg.ChannelID + "/" + msg.ID
+		if !seen[key] {
+			seen[key] = true
 			result = append(result, msg)
 		}
 	}
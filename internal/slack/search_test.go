@@ -0,0 +1,244 @@
+package slack_test
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	slago "github.com/longkey1/slago/internal/slack"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestSearchQueries(t *testing.T) {
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		opts slago.SearchOptions
+		want []string
+	}{
+		{
+			name: "author and date window",
+			opts: slago.SearchOptions{Author: "U1", After: day.AddDate(0, 0, -1), Before: day.AddDate(0, 0, 1)},
+			want: []string{"from:<@U1> -is:dm -is:mpdm after:2025-01-14 before:2025-01-16"},
+		},
+		{
+			name: "keywords, has and raw query",
+			opts: slago.SearchOptions{
+				Keywords:   []string{"deploy", "release notes", `"rollback plan"`},
+				Has:        []string{"link", "has:pin"},
+				Query:      " is:thread -foo ",
+				Channels:   []string{"general"},
+				IncludeDMs: true,
+			},
+			want: []string{`deploy "release notes" "rollback plan" has:link has:pin is:thread -foo in:general -is:mpdm`},
+		},
+		{
+			name: "mention alternatives",
+			opts: slago.SearchOptions{
+				Mentions:     []slago.MentionFilter{{Terms: []string{"<@U1>", "to:U1"}}},
+				Keywords:     []string{"incident"},
+				IncludeDMs:   true,
+				IncludeMPDMs: true,
+			},
+			want: []string{"<@U1> incident", "to:U1 incident"},
+		},
+		{
+			name: "checked mentions are not combined",
+			opts: slago.SearchOptions{
+				Mentions: []slago.MentionFilter{
+					{Terms: []string{"<!subteam^S1>", "<@U1>", "<@U2>"}, IDs: []string{"S1", "U1", "U2"}},
+					{Terms: []string{"<!subteam^S2>", "<@U3>"}, IDs: []string{"S2", "U3"}},
+					{Terms: []string{"@backend"}},
+				},
+				IncludeDMs:   true,
+				IncludeMPDMs: true,
+			},
+			want: []string{"@backend <!subteam^S2>", "@backend <@U3>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slago.SearchQueries(tt.opts)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchQueries() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchMessagesSplitsCappedWindow(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name     string
+		loc      *time.Location
+		offset   time.Duration
+		count    int
+		interval time.Duration
+		want     int
+	}{
+		// 250 matches over the day: halves and quarters fit under the cap
+		{name: "busy day", loc: time.UTC, count: 250, interval: 5 * time.Minute, want: 250},
+		// 150 matches within one hour, which is not split further
+		{name: "busy hour", loc: time.UTC, offset: 10 * time.Hour, count: 150, interval: 20 * time.Second, want: 100},
+		// Hour bounds are written in the zone of the day bounds
+		{name: "busy day in another zone", loc: tokyo, count: 250, interval: 5 * time.Minute, want: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := slacktest.NewServer()
+			defer srv.Close()
+
+			srv.SetSearchLocation(tt.loc)
+
+			day := time.Date(2025, 1, 15, 0, 0, 0, 0, tt.loc)
+			for i := range tt.count {
+				ts := fmt.Sprintf("%d.000100", day.Add(tt.offset+time.Duration(i)*tt.interval).Unix())
+				srv.AddSearchMatch(slackapi.SearchMessage{
+					Channel:   slackapi.CtxChannel{ID: "C1", Name: "general"},
+					Timestamp: ts,
+					Permalink: slacktest.Permalink("C1", ts),
+				})
+			}
+
+			client := srv.Client(slago.WithSearchPageLimit(1))
+			messages, err := client.SearchMessages(slago.SearchOptions{
+				After:  day.AddDate(0, 0, -1),
+				Before: day.AddDate(0, 0, 1),
+			})
+			if err != nil {
+				t.Fatalf("SearchMessages() error = %v", err)
+			}
+			if len(messages) != tt.want {
+				t.Errorf("SearchMessages() = %d messages, want %d", len(messages), tt.want)
+			}
+
+			queries := srv.Queries()
+			if len(queries) < 3 || !strings.Contains(queries[1], "after:2025-01-14T23:59 before:2025-01-15T12:01") {
+				t.Errorf("queries = %q, want the day split at noon", queries)
+			}
+		})
+	}
+}
+
+func TestSearchMessagesKeepsUnsplitResults(t *testing.T) {
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	var matches []slackapi.SearchMessage
+	for i := range 250 {
+		ts := fmt.Sprintf("%d.000100", day.Add(time.Duration(i)*5*time.Minute).Unix())
+		matches = append(matches, slackapi.SearchMessage{
+			Channel:   slackapi.CtxChannel{ID: "C1", Name: "general"},
+			Timestamp: ts,
+			Permalink: slacktest.Permalink("C1", ts),
+		})
+	}
+
+	tests := []struct {
+		name   string
+		hourly []slackapi.SearchMessage
+	}{
+		// A search that finds nothing for hour-precision bounds
+		{name: "hour bounds find nothing", hourly: nil},
+		// A search that ignores hour-precision bounds, so no split narrows
+		{name: "hour bounds ignored", hourly: matches},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := slacktest.NewServer()
+			defer srv.Close()
+
+			srv.Handle("search.messages", func(w http.ResponseWriter, r *http.Request) {
+				found := matches
+				if strings.Contains(r.FormValue("query"), "T") {
+					found = tt.hourly
+				}
+				page, _ := strconv.Atoi(r.FormValue("page"))
+				page = max(page, 1)
+				start := min((page-1)*100, len(found))
+				end := min(start+100, len(found))
+				slacktest.WriteJSON(w, map[string]interface{}{
+					"ok": true,
+					"messages": map[string]interface{}{
+						"matches": found[start:end],
+						"total":   len(found),
+						"paging":  slackapi.Paging{Count: 100, Total: len(found), Page: page, Pages: (len(found) + 99) / 100},
+					},
+				})
+			})
+
+			client := srv.Client(slago.WithSearchPageLimit(1))
+			messages, err := client.SearchMessages(slago.SearchOptions{
+				After:  day.AddDate(0, 0, -1),
+				Before: day.AddDate(0, 0, 1),
+			})
+			if err != nil {
+				t.Fatalf("SearchMessages() error = %v", err)
+			}
+			if len(messages) != 100 {
+				t.Errorf("SearchMessages() = %d messages, want the 100 of the unsplit query", len(messages))
+			}
+			// The unsplit query, plus at most four times its 3 pages for the split
+			if calls := srv.Calls("search.messages"); calls > 13 {
+				t.Errorf("search.messages called %d times, want at most 13", calls)
+			}
+		})
+	}
+}
+
+func TestSearchMessagesKeepsChannelsApart(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	parentTS := "1736935200.000100"
+	replyTS := "1736935260.000200"
+	standaloneTS := "1736938800.000300"
+	for _, ch := range []string{"C1", "C2"} {
+		parent := slackapi.Message{}
+		parent.Timestamp = parentTS
+		parent.ThreadTimestamp = parentTS
+		reply := slackapi.Message{}
+		reply.Timestamp = replyTS
+		reply.ThreadTimestamp = parentTS
+		srv.AddMessage(ch, parent)
+		srv.AddMessage(ch, reply)
+		srv.AddSearchMatch(slackapi.SearchMessage{
+			Channel:   slackapi.CtxChannel{ID: ch, Name: ch},
+			Timestamp: replyTS,
+			Permalink: slacktest.Permalink(ch, replyTS) + "?thread_ts=" + parentTS,
+		})
+		srv.AddSearchMatch(slackapi.SearchMessage{
+			Channel:   slackapi.CtxChannel{ID: ch, Name: ch},
+			Timestamp: standaloneTS,
+			Permalink: slacktest.Permalink(ch, standaloneTS),
+		})
+	}
+
+	messages, err := srv.Client().SearchMessages(slago.SearchOptions{
+		After:  day.AddDate(0, 0, -1),
+		Before: day.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+
+	var got []string
+	for _, msg := range messages {
+		got = append(got, msg.ChannelID+"/"+msg.ID)
+	}
+	slices.Sort(got)
+	want := []string{
+		"C1/" + parentTS, "C1/" + replyTS, "C1/" + standaloneTS,
+		"C2/" + parentTS, "C2/" + replyTS, "C2/" + standaloneTS,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("SearchMessages() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,586 @@
+// Package slacktest provides an in-process stand-in for the Slack Web API
+// so that collectors can be exercised without a real workspace.
+package slacktest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/longkey1/slago/internal/model"
+	slago "github.com/longkey1/slago/internal/slack"
+	"github.com/slack-go/slack"
+)
+
+// Server serves canned Slack Web API responses over a local HTTP server
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc
+	channels map[string]slack.Channel
+	members  map[string][]string
+	messages map[string][]slack.Message
+	users    map[string]slack.User
+	groups   []slack.UserGroup
+	auth     slack.AuthTestResponse
+	scopes   []string
+	matches  []slack.SearchMessage
+	location *time.Location
+	queries  []string
+	calls    map[string]int
+	files    map[string][]byte
+
+	// Socket Mode stand-in
+	conns        []*websocket.Conn
+	socketEvents chan []byte
+	envelopes    int
+	acks         []string
+}
+
+// NewServer starts a new fake Slack server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]http.HandlerFunc),
+		channels: make(map[string]slack.Channel),
+		members:  make(map[string][]string),
+		messages: make(map[string][]slack.Message),
+		users:    make(map[string]slack.User),
+		calls:    make(map[string]int),
+		files:    make(map[string][]byte),
+		location: time.UTC,
+		// Room for the events a test queues ahead of a connection
+		socketEvents: make(chan []byte, 100),
+		auth: slack.AuthTestResponse{
+			URL:    "https://example.slack.com/",
+			Team:   "Example",
+			TeamID: "T0000000",
+			User:   "tester",
+			UserID: "U0000000",
+		},
+	}
+
+	s.handlers["search.messages"] = s.handleSearchMessages
+	s.handlers["conversations.replies"] = s.handleConversationsReplies
+	s.handlers["conversations.info"] = s.handleConversationsInfo
+	s.handlers["conversations.list"] = s.handleConversationsList
+	s.handlers["conversations.history"] = s.handleConversationsHistory
+	s.handlers["conversations.members"] = s.handleConversationsMembers
+	s.handlers["chat.getPermalink"] = s.handleGetPermalink
+	s.handlers["reactions.get"] = s.handleReactionsGet
+	s.handlers["users.info"] = s.handleUsersInfo
+	s.handlers["users.list"] = s.handleUsersList
+	s.handlers["users.lookupByEmail"] = s.handleUsersLookupByEmail
+	s.handlers["auth.test"] = s.handleAuthTest
+	s.handlers["usergroups.list"] = s.handleUserGroupsList
+	s.handlers["apps.connections.open"] = s.handleAppsConnectionsOpen
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// APIURL returns the Web API base URL of the server
+func (s *Server) APIURL() string {
+	return s.URL + "/"
+}
+
+// Client returns a slago client talking to this server
+func (s *Server) Client(opts ...slago.Option) *slago.Client {
+	opts = append([]slago.Option{
+		slago.WithAPIURL(s.APIURL()),
+		slago.WithRateLimiter(slago.NewRateLimiter(nil)),
+	}, opts...)
+	return slago.NewClient("xoxp-test", opts...)
+}
+
+// Handle overrides the handler for a Web API method
+func (s *Server) Handle(method string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = h
+}
+
+// AddChannel registers a channel the token is a member of
+func (s *Server) AddChannel(id, name string) {
+	ch := slack.Channel{}
+	ch.ID = id
+	ch.Name = name
+	ch.IsMember = true
+	s.AddConversation(ch)
+}
+
+// AddConversation registers a conversation returned by conversations.info and conversations.list
+func (s *Server) AddConversation(ch slack.Channel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels[ch.ID] = ch
+}
+
+// SetMembers sets the members returned by conversations.members
+func (s *Server) SetMembers(channelID string, members []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members[channelID] = members
+}
+
+// AddMessage registers a message (parent or reply) returned by
+// conversations.replies, reactions.get and, for top-level messages,
+// conversations.history
+func (s *Server) AddMessage(channelID string, msg slack.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[channelID] = append(s.messages[channelID], msg)
+}
+
+// AddFile registers the content of a file and returns it with url_private
+// and size pointing at this server. Downloads are counted as "files" calls.
+func (s *Server) AddFile(file slack.File, content []byte) slack.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file.URLPrivate = s.URL + "/" + filesPath + file.ID
+	file.Size = len(content)
+	s.files[file.ID] = content
+	return file
+}
+
+// AddUser registers a user returned by users.info and users.list
+func (s *Server) AddUser(user slack.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user.ID] = user
+}
+
+// AddUserGroup registers a user group returned by usergroups.list
+func (s *Server) AddUserGroup(group slack.UserGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups = append(s.groups, group)
+}
+
+// SetAuth sets the identity returned by auth.test
+func (s *Server) SetAuth(auth slack.AuthTestResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = auth
+}
+
+// SetScopes sets the scopes reported in auth.test's X-OAuth-Scopes header
+func (s *Server) SetScopes(scopes ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scopes = scopes
+}
+
+// SetSearchLocation sets the zone search.messages reads after: and before:
+// bounds in, UTC by default
+func (s *Server) SetSearchLocation(loc *time.Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.location = loc
+}
+
+// AddSearchMatch registers a match returned by search.messages
+func (s *Server) AddSearchMatch(match slack.SearchMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matches = append(s.matches, match)
+}
+
+// Queries returns the search queries received so far
+func (s *Server) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+// Calls returns how many times a Web API method has been called
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/")
+	if strings.HasPrefix(method, filesPath) {
+		s.serveFile(w, r, strings.TrimPrefix(method, filesPath))
+		return
+	}
+	if method == socketPath {
+		s.serveSocket(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[method]++
+	h, ok := s.handlers[method]
+	s.mu.Unlock()
+
+	if !ok {
+		WriteError(w, "unknown_method")
+		return
+	}
+	h(w, r)
+}
+
+// filesPath is the URL prefix file downloads are served under
+const filesPath = "files/"
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	s.calls["files"]++
+	content, ok := s.files[id]
+	s.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	_, _ = w.Write(content)
+}
+
+func (s *Server) handleSearchMessages(w http.ResponseWriter, r *http.Request) {
+	count := formInt(r, "count", 20)
+	page := formInt(r, "page", 1)
+
+	s.mu.Lock()
+	s.queries = append(s.queries, r.FormValue("query"))
+	inWindow := searchWindow(r.FormValue("query"), s.location)
+	var all []slack.SearchMessage
+	for _, match := range s.matches {
+		if inWindow(match.Timestamp) {
+			all = append(all, match)
+		}
+	}
+	s.mu.Unlock()
+
+	total := len(all)
+	start := min((page-1)*count, total)
+	end := min(start+count, total)
+	matches := all[start:end]
+
+	pages := (total + count - 1) / count
+	WriteJSON(w, map[string]interface{}{
+		"ok":    true,
+		"query": r.FormValue("query"),
+		"messages": map[string]interface{}{
+			"matches": matches,
+			"total":   total,
+			"paging": slack.Paging{
+				Count: count,
+				Total: total,
+				Page:  page,
+				Pages: pages,
+			},
+		},
+	})
+}
+
+// searchWindow applies a query's after: and before: bounds, given as dates
+// (which are excluded) or as times to the minute, in the search's zone
+func searchWindow(query string, loc *time.Location) func(ts string) bool {
+	var after, before time.Time
+	for _, term := range strings.Fields(query) {
+		name, value, _ := strings.Cut(term, ":")
+		var bound time.Time
+		if t, err := time.ParseInLocation("2006-01-02T15:04", value, loc); err == nil {
+			bound = t
+		} else if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+			bound = t
+			if name == "after" {
+				bound = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		} else {
+			continue
+		}
+		switch name {
+		case "after":
+			after = bound
+		case "before":
+			before = bound
+		}
+	}
+
+	return func(ts string) bool {
+		t := model.TS(ts).Time()
+		return (after.IsZero() || t.After(after)) && (before.IsZero() || t.Before(before))
+	}
+}
+
+func (s *Server) handleConversationsReplies(w http.ResponseWriter, r *http.Request) {
+	channelID := r.FormValue("channel")
+	threadTS := r.FormValue("ts")
+	limit := formInt(r, "limit", 1000)
+	offset := formInt(r, "cursor", 0)
+
+	s.mu.Lock()
+	var thread []slack.Message
+	for _, msg := range s.messages[channelID] {
+		switch {
+		case msg.Timestamp == threadTS:
+			thread = append(thread, s.withReplies(channelID, msg))
+		case msg.ThreadTimestamp == threadTS:
+			thread = append(thread, msg)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(thread) == 0 {
+		WriteError(w, "thread_not_found")
+		return
+	}
+
+	sort.Slice(thread, func(i, j int) bool {
+		return model.TS(thread[i].Timestamp).Before(model.TS(thread[j].Timestamp))
+	})
+
+	start := min(offset, len(thread))
+	end := min(start+limit, len(thread))
+	resp := map[string]interface{}{
+		"ok":       true,
+		"messages": thread[start:end],
+		"has_more": end < len(thread),
+	}
+	if end < len(thread) {
+		resp["response_metadata"] = map[string]string{"next_cursor": strconv.Itoa(end)}
+	}
+	WriteJSON(w, resp)
+}
+
+func (s *Server) handleConversationsInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	ch, ok := s.channels[r.FormValue("channel")]
+	s.mu.Unlock()
+
+	if !ok {
+		WriteError(w, "channel_not_found")
+		return
+	}
+	WriteJSON(w, map[string]interface{}{"ok": true, "channel": ch})
+}
+
+func (s *Server) handleConversationsList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	channels := make([]slack.Channel, 0, len(s.channels))
+	for _, ch := range s.channels {
+		channels = append(channels, ch)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ID < channels[j].ID
+	})
+	WriteJSON(w, map[string]interface{}{"ok": true, "channels": channels})
+}
+
+func (s *Server) handleConversationsHistory(w http.ResponseWriter, r *http.Request) {
+	channelID := r.FormValue("channel")
+	oldest := r.FormValue("oldest")
+	latest := r.FormValue("latest")
+	limit := formInt(r, "limit", 100)
+	offset := formInt(r, "cursor", 0)
+
+	s.mu.Lock()
+	_, ok := s.channels[channelID]
+	var history []slack.Message
+	for _, msg := range s.messages[channelID] {
+		if msg.ThreadTimestamp != "" && msg.ThreadTimestamp != msg.Timestamp {
+			continue
+		}
+		if (oldest != "" && model.TS(msg.Timestamp).Before(model.TS(oldest))) || (latest != "" && model.TS(latest).Before(model.TS(msg.Timestamp))) {
+			continue
+		}
+		history = append(history, s.withReplies(channelID, msg))
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		WriteError(w, "channel_not_found")
+		return
+	}
+
+	// Newest first, like Slack
+	sort.Slice(history, func(i, j int) bool {
+		return model.TS(history[j].Timestamp).Before(model.TS(history[i].Timestamp))
+	})
+
+	start := min(offset, len(history))
+	end := min(start+limit, len(history))
+	resp := map[string]interface{}{
+		"ok":       true,
+		"messages": history[start:end],
+		"has_more": end < len(history),
+	}
+	if end < len(history) {
+		resp["response_metadata"] = map[string]string{"next_cursor": strconv.Itoa(end)}
+	}
+	WriteJSON(w, resp)
+}
+
+// withReplies sets the reply count and latest reply of a thread parent from
+// the replies registered so far
+func (s *Server) withReplies(channelID string, parent slack.Message) slack.Message {
+	count := 0
+	for _, msg := range s.messages[channelID] {
+		if msg.ThreadTimestamp != parent.Timestamp || msg.Timestamp == parent.Timestamp {
+			continue
+		}
+		count++
+		if parent.LatestReply == "" || model.TS(parent.LatestReply).Before(model.TS(msg.Timestamp)) {
+			parent.LatestReply = msg.Timestamp
+		}
+	}
+	if count > 0 {
+		parent.ReplyCount = count
+	}
+	return parent
+}
+
+func (s *Server) handleConversationsMembers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	members, ok := s.members[r.FormValue("channel")]
+	s.mu.Unlock()
+
+	if !ok {
+		WriteError(w, "channel_not_found")
+		return
+	}
+	WriteJSON(w, map[string]interface{}{"ok": true, "members": members})
+}
+
+func (s *Server) handleGetPermalink(w http.ResponseWriter, r *http.Request) {
+	channelID := r.FormValue("channel")
+	ts := r.FormValue("message_ts")
+	WriteJSON(w, map[string]interface{}{
+		"ok":        true,
+		"channel":   channelID,
+		"permalink": Permalink(channelID, ts),
+	})
+}
+
+func (s *Server) handleReactionsGet(w http.ResponseWriter, r *http.Request) {
+	channelID := r.FormValue("channel")
+	ts := r.FormValue("timestamp")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range s.messages[channelID] {
+		if msg.Timestamp == ts {
+			WriteJSON(w, map[string]interface{}{
+				"ok":      true,
+				"type":    "message",
+				"channel": channelID,
+				"message": msg,
+			})
+			return
+		}
+	}
+	WriteError(w, "message_not_found")
+}
+
+func (s *Server) handleUsersInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	user, ok := s.users[r.FormValue("user")]
+	s.mu.Unlock()
+
+	if !ok {
+		WriteError(w, "user_not_found")
+		return
+	}
+	WriteJSON(w, map[string]interface{}{"ok": true, "user": user})
+}
+
+func (s *Server) handleUsersList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	members := make([]slack.User, 0, len(s.users))
+	for _, user := range s.users {
+		members = append(members, user)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+	WriteJSON(w, map[string]interface{}{"ok": true, "members": members})
+}
+
+func (s *Server) handleUsersLookupByEmail(w http.ResponseWriter, r *http.Request) {
+	email := r.FormValue("email")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if strings.EqualFold(user.Profile.Email, email) {
+			WriteJSON(w, map[string]interface{}{"ok": true, "user": user})
+			return
+		}
+	}
+	WriteError(w, "users_not_found")
+}
+
+func (s *Server) handleAuthTest(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	auth := s.auth
+	scopes := s.scopes
+	s.mu.Unlock()
+
+	if scopes != nil {
+		w.Header().Set("X-OAuth-Scopes", strings.Join(scopes, ","))
+	}
+
+	WriteJSON(w, map[string]interface{}{
+		"ok":      true,
+		"url":     auth.URL,
+		"team":    auth.Team,
+		"team_id": auth.TeamID,
+		"user":    auth.User,
+		"user_id": auth.UserID,
+		"bot_id":  auth.BotID,
+	})
+}
+
+func (s *Server) handleUserGroupsList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	groups := append([]slack.UserGroup(nil), s.groups...)
+	s.mu.Unlock()
+
+	WriteJSON(w, map[string]interface{}{"ok": true, "usergroups": groups})
+}
+
+// Permalink builds the permalink the fake server reports for a message
+func Permalink(channelID, ts string) string {
+	return "https://example.slack.com/archives/" + channelID + "/p" + strings.ReplaceAll(ts, ".", "")
+}
+
+// WriteJSON writes v as a JSON response
+func WriteJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes a Slack-style error response
+func WriteError(w http.ResponseWriter, code string) {
+	WriteJSON(w, map[string]interface{}{"ok": false, "error": code})
+}
+
+func formInt(r *http.Request, key string, def int) int {
+	v, err := strconv.Atoi(r.FormValue(key))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
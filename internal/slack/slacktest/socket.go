@@ -0,0 +1,147 @@
+package slacktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/slack-go/slack"
+)
+
+// socketPath is the URL the Socket Mode stand-in accepts WebSocket
+// connections on
+const socketPath = "socket"
+
+// socketPingInterval is how often connections are pinged, like Slack does
+// to keep them alive
+const socketPingInterval = time.Second
+
+var upgrader = websocket.Upgrader{
+	// socketmode sends Origin: https://api.slack.com
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// SendMessage queues a message event for the Socket Mode connection and
+// returns its envelope ID. Events wait until a client is connected.
+func (s *Server) SendMessage(channelID string, msg slack.Message) string {
+	msg.Type = slack.TYPE_MESSAGE
+	msg.Channel = channelID
+	return s.SendEvent(msg)
+}
+
+// SendEvent queues an Events API event for the Socket Mode connection and
+// returns its envelope ID
+func (s *Server) SendEvent(event interface{}) string {
+	s.mu.Lock()
+	s.envelopes++
+	id := fmt.Sprintf("envelope-%d", s.envelopes)
+	s.mu.Unlock()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"type":        "events_api",
+		"envelope_id": id,
+		"payload": map[string]interface{}{
+			"type":  "event_callback",
+			"event": event,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	s.socketEvents <- data
+	return id
+}
+
+// DropConnections closes the open Socket Mode connections, as a network
+// failure would
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = nil
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+// Acks returns the envelope IDs acknowledged so far
+func (s *Server) Acks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.acks...)
+}
+
+// Close drops the Socket Mode connections and shuts the server down
+func (s *Server) Close() {
+	s.DropConnections()
+	s.Server.Close()
+}
+
+func (s *Server) handleAppsConnectionsOpen(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer xapp-") {
+		WriteError(w, "invalid_auth")
+		return
+	}
+	WriteJSON(w, map[string]interface{}{
+		"ok":  true,
+		"url": "ws" + strings.TrimPrefix(s.URL, "http") + "/" + socketPath,
+	})
+}
+
+// serveSocket greets a Socket Mode client, pings it and writes queued
+// events to it until the connection is dropped
+func (s *Server) serveSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	s.mu.Unlock()
+
+	// Read acknowledgements until the connection is closed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var ack struct {
+				EnvelopeID string `json:"envelope_id"`
+			}
+			if err := conn.ReadJSON(&ack); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.acks = append(s.acks, ack.EnvelopeID)
+			s.mu.Unlock()
+		}
+	}()
+
+	if err := conn.WriteJSON(map[string]interface{}{"type": "hello", "num_connections": 1}); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(socketPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return
+			}
+		case data := <-s.socketEvents:
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				// Keep the event for the next connection
+				s.socketEvents <- data
+				return
+			}
+		}
+	}
+}
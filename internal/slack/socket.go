@@ -0,0 +1,116 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// Listen connects over Socket Mode with an app-level token (xapp-) and calls
+// handle with every message posted or edited until ctx is done. Dropped
+// connections are reopened, waiting longer after each failed attempt.
+// Handle is called from one goroutine at a time.
+func (c *Client) Listen(ctx context.Context, appToken string, handle func(model.Message)) error {
+	// socketmode keeps retrying tokens Slack rejects for the wrong type
+	if tokenType, _ := TokenType(appToken); tokenType != TokenTypeApp {
+		return fmt.Errorf("socket mode needs an app-level token (xapp-), got a %s token", tokenType)
+	}
+
+	apiOpts := []slack.Option{slack.OptionAppLevelToken(appToken)}
+	if c.apiURL != "" {
+		apiOpts = append(apiOpts, slack.OptionAPIURL(c.apiURL))
+	}
+	smc := socketmode.New(slack.New(c.token, apiOpts...))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case evt := <-smc.Events:
+				c.handleSocketEvent(runCtx, smc, evt, handle)
+			}
+		}
+	}()
+
+	// RunContext only returns when ctx is done or the token is rejected
+	err := smc.RunContext(runCtx)
+	cancel()
+	<-done
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return fmt.Errorf("socket mode error: %w", err)
+}
+
+// handleSocketEvent reports connection changes and passes on messages
+func (c *Client) handleSocketEvent(ctx context.Context, smc *socketmode.Client, evt socketmode.Event, handle func(model.Message)) {
+	switch evt.Type {
+	case socketmode.EventTypeConnecting:
+		if e, ok := evt.Data.(*slack.ConnectingEvent); ok && e.Attempt > 1 {
+			fmt.Printf("[INFO] Reconnecting (attempt %d)\n", e.Attempt)
+		}
+	case socketmode.EventTypeConnectionError:
+		if e, ok := evt.Data.(*slack.ConnectionErrorEvent); ok {
+			fmt.Printf("[WARN] Connection failed: %v, retrying in %v\n", e.ErrorObj, e.Backoff)
+		}
+	case socketmode.EventTypeConnected:
+		fmt.Println("[INFO] Connected to Slack")
+	case socketmode.EventTypeDisconnect:
+		fmt.Println("[INFO] Slack asked to reconnect")
+	case socketmode.EventTypeEventsAPI:
+		if evt.Request == nil {
+			return
+		}
+		// Slack resends events that are not acknowledged within 3 seconds
+		if err := smc.AckCtx(ctx, evt.Request.EnvelopeID, nil); err != nil {
+			return
+		}
+		if msg, ok := c.socketMessage(evt.Request.Payload); ok {
+			handle(msg)
+		}
+	}
+}
+
+// socketMessage converts the message event of an Events API payload. Edits
+// carry the edited message; deletions and other events are skipped.
+func (c *Client) socketMessage(payload json.RawMessage) (model.Message, bool) {
+	var callback struct {
+		Event json.RawMessage `json:"event"`
+	}
+	if err := json.Unmarshal(payload, &callback); err != nil || callback.Event == nil {
+		return model.Message{}, false
+	}
+
+	var event slack.Message
+	if err := json.Unmarshal(callback.Event, &event); err != nil {
+		fmt.Printf("[WARN] Failed to parse message event: %v\n", err)
+		return model.Message{}, false
+	}
+	if event.Type != slack.TYPE_MESSAGE {
+		return model.Message{}, false
+	}
+
+	msg := event
+	switch event.SubType {
+	case slack.MsgSubTypeMessageChanged:
+		if event.SubMessage == nil {
+			return model.Message{}, false
+		}
+		msg = slack.Message{Msg: *event.SubMessage}
+	case slack.MsgSubTypeMessageDeleted, slack.MsgSubTypeMessageReplied:
+		return model.Message{}, false
+	}
+
+	return c.convertReplyMessage(msg, event.Channel, ""), true
+}
@@ -1,16 +1,16 @@
 package slack
 
 import (
+	"encoding/json"
 	"fmt"
-	"regexp"
+	"os"
 	"time"
 
 	"github.com/longkey1/slago/internal/model"
+	"github.com/longkey1/slago/internal/mrkdwn"
 	"github.com/slack-go/slack"
 )
 
-const maxRetries = 5
-
 // GetThreadReplies fetches all replies in a thread
 func (c *Client) GetThreadReplies(channelID, threadTS string) ([]model.Message, error) {
 	var allMessages []model.Message
@@ -27,29 +27,13 @@ func (c *Client) GetThreadReplies(channelID, threadTS string) ([]model.Message,
 		var msgs []slack.Message
 		var hasMore bool
 		var nextCursor string
-		var err error
-
-		// Retry with exponential backoff for rate limits
-		for retry := 0; retry < maxRetries; retry++ {
+		err := c.call(Tier3, func() error {
+			var err error
 			msgs, hasMore, nextCursor, err = c.api.GetConversationReplies(params)
-			if err == nil {
-				break
-			}
-
-			if rateLimitErr, ok := err.(*slack.RateLimitedError); ok {
-				waitTime := rateLimitErr.RetryAfter
-				if waitTime == 0 {
-					waitTime = time.Duration(1<<retry) * time.Second
-				}
-				time.Sleep(waitTime)
-				continue
-			}
-
-			return nil, fmt.Errorf("conversations.replies API error: %w", err)
-		}
-
+			return err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("conversations.replies API error after retries: %w", err)
+			return nil, fmt.Errorf("conversations.replies API error: %w", err)
 		}
 
 		for _, msg := range msgs {
@@ -60,9 +44,6 @@ func (c *Client) GetThreadReplies(channelID, threadTS string) ([]model.Message,
 			break
 		}
 		cursor = nextCursor
-
-		// Rate limit prevention
-		time.Sleep(time.Second)
 	}
 
 	return allMessages, nil
@@ -72,24 +53,16 @@ func (c *Client) GetThreadReplies(channelID, threadTS string) ([]model.Message,
 func (c *Client) GetThread(channelID, threadTS string) (*model.Thread, error) {
 	// Get channel info
 	channelName := channelID
-	channelInfo, err := c.api.GetConversationInfo(&slack.GetConversationInfoInput{
-		ChannelID: channelID,
-	})
+	channelType := ""
+	isPrivate := false
+	channel, err := c.GetChannel(channelID)
 	if err != nil {
 		// Just use channel ID if we can't get the name (might be missing scope)
-		fmt.Printf("[WARN] Could not get channel info: %v\n", err)
+		fmt.Fprintf(os.Stderr, "[WARN] Could not get channel info: %v\n", err)
 	} else {
-		channelName = channelInfo.Name
-	}
-
-	// Get permalink for the thread
-	permalink := ""
-	permalinkResp, err := c.api.GetPermalink(&slack.PermalinkParameters{
-		Channel: channelID,
-		Ts:      threadTS,
-	})
-	if err == nil {
-		permalink = permalinkResp
+		channelName = channel.Name
+		channelType = channel.Type()
+		isPrivate = channelType != model.ChannelTypePublic
 	}
 
 	// Get thread messages
@@ -102,25 +75,55 @@ func (c *Client) GetThread(channelID, threadTS string) (*model.Thread, error) {
 	for i := range messages {
 		messages[i].Channel = channelName
 		messages[i].ChannelID = channelID
+		messages[i].ChannelType = channelType
+		messages[i].IsPrivate = isPrivate
 	}
 
 	return &model.Thread{
-		ThreadID:        threadTS,
-		ThreadPermalink: permalink,
-		Channel:         channelName,
-		ChannelID:       channelID,
-		Messages:        messages,
-		MessageCount:    len(messages),
+		ThreadID:     threadTS,
+		Channel:      channelName,
+		ChannelID:    channelID,
+		ChannelType:  channelType,
+		IsPrivate:    isPrivate,
+		Messages:     messages,
+		MessageCount: len(messages),
 	}, nil
 }
 
+// GetPermalink fetches the permalink for a message
+func (c *Client) GetPermalink(channelID, ts string) (string, error) {
+	var permalink string
+	err := c.call(Tier4, func() error {
+		var err error
+		permalink, err = c.api.GetPermalink(&slack.PermalinkParameters{
+			Channel: channelID,
+			Ts:      ts,
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("chat.getPermalink API error: %w", err)
+	}
+	return permalink, nil
+}
+
 func (c *Client) convertReplyMessage(msg slack.Message, channelID, channelName string) model.Message {
-	ts := c.parseTimestamp(msg.Timestamp)
+	ts := model.TS(msg.Timestamp).Time()
 	threadTS := msg.ThreadTimestamp
 	if threadTS == "" {
 		threadTS = msg.Timestamp
 	}
 
+	var editedAt *time.Time
+	var editedBy string
+	if msg.Edited != nil {
+		if t := model.TS(msg.Edited.Timestamp); t.Valid() {
+			at := t.Time()
+			editedAt = &at
+		}
+		editedBy = msg.Edited.User
+	}
+
 	return model.Message{
 		ID:             msg.Timestamp,
 		Type:           "slack_message",
@@ -129,47 +132,46 @@ func (c *Client) convertReplyMessage(msg slack.Message, channelID, channelName s
 		Timestamp:      ts,
 		Channel:        channelName,
 		ChannelID:      channelID,
-		Mentions:       c.extractMentionsFromText(msg.Text),
-		AttachedLinks:  c.extractLinksFromMessage(msg),
+		Mentions:       mrkdwn.Mentions(msg.Text),
+		AttachedLinks:  extractLinks(msg.Text, msg.Attachments),
 		ThreadTS:       threadTS,
 		IsThreadParent: threadTS == "" || threadTS == msg.Timestamp,
+		ReplyCount:     msg.ReplyCount,
+		LatestReply:    msg.LatestReply,
+		Reactions:      convertReactions(msg.Reactions),
+		Files:          convertFiles(msg.Files),
+		Blocks:         rawBlocks(msg.Blocks),
+		EditedAt:       editedAt,
+		EditedBy:       editedBy,
+		Subtype:        msg.SubType,
+		BotID:          msg.BotID,
+		BotName:        botName(msg),
 	}
 }
 
-func (c *Client) extractMentionsFromText(text string) []string {
-	re := regexp.MustCompile(`<@([^|>]+)\|([^>]+)>`)
-	matches := re.FindAllStringSubmatch(text, -1)
-
-	seen := make(map[string]bool)
-	var mentions []string
-	for _, match := range matches {
-		if len(match) > 2 && !seen[match[2]] {
-			mentions = append(mentions, match[2])
-			seen[match[2]] = true
-		}
+// botName names the bot that posted a message. Integrations set username,
+// apps carry a bot profile.
+func botName(msg slack.Message) string {
+	if msg.BotID == "" && msg.SubType != slack.MsgSubTypeBotMessage {
+		return ""
 	}
-	return mentions
+	if msg.Username != "" {
+		return msg.Username
+	}
+	if msg.BotProfile != nil {
+		return msg.BotProfile.Name
+	}
+	return ""
 }
 
-func (c *Client) extractLinksFromMessage(msg slack.Message) []string {
-	seen := make(map[string]bool)
-	var links []string
-
-	re := regexp.MustCompile(`https?://[^\s>]+`)
-	textLinks := re.FindAllString(msg.Text, -1)
-	for _, link := range textLinks {
-		if !seen[link] {
-			links = append(links, link)
-			seen[link] = true
-		}
+// rawBlocks keeps the blocks of a message as JSON
+func rawBlocks(blocks slack.Blocks) json.RawMessage {
+	if len(blocks.BlockSet) == 0 {
+		return nil
 	}
-
-	for _, att := range msg.Attachments {
-		if att.TitleLink != "" && !seen[att.TitleLink] {
-			links = append(links, att.TitleLink)
-			seen[att.TitleLink] = true
-		}
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		return nil
 	}
-
-	return links
+	return data
 }
@@ -2,6 +2,7 @@ package slack
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -39,6 +40,26 @@ func ParseURL(url string) (*URLInfo, error) {
 	return info, nil
 }
 
+// WorkspaceURL returns the workspace base URL ("https://xxx.slack.com/") of
+// a Slack URL
+func WorkspaceURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid Slack URL: %s", rawURL)
+	}
+	return u.Scheme + "://" + u.Host + "/", nil
+}
+
+// Permalink builds the permalink of a message without calling
+// chat.getPermalink. Replies link to their thread with thread_ts.
+func Permalink(workspaceURL, channelID, ts, threadTS string) string {
+	link := strings.TrimSuffix(workspaceURL, "/") + "/archives/" + channelID + "/p" + strings.ReplaceAll(ts, ".", "")
+	if threadTS != "" && threadTS != ts {
+		link += "?thread_ts=" + threadTS
+	}
+	return link
+}
+
 // normalizeTimestamp converts a timestamp to the format "seconds.microseconds"
 func normalizeTimestamp(raw string) string {
 	if strings.Contains(raw, ".") {
@@ -84,3 +84,52 @@ func TestNormalizeTimestamp(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkspaceURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{"https://example.slack.com/archives/C123/p1716192523567890", "https://example.slack.com/", false},
+		{"https://example.slack.com/", "https://example.slack.com/", false},
+		{"not a url", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := WorkspaceURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("WorkspaceURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("WorkspaceURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestPermalink(t *testing.T) {
+	tests := []struct {
+		name     string
+		ts       string
+		threadTS string
+		want     string
+	}{
+		{"parent", "1716192523.567890", "1716192523.567890", "https://example.slack.com/archives/C123/p1716192523567890"},
+		{"standalone", "1716192523.567890", "", "https://example.slack.com/archives/C123/p1716192523567890"},
+		{"reply", "1716192600.000100", "1716192523.567890", "https://example.slack.com/archives/C123/p1716192600000100?thread_ts=1716192523.567890"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Permalink("https://example.slack.com/", "C123", tt.ts, tt.threadTS)
+			if got != tt.want {
+				t.Errorf("Permalink() = %q, want %q", got, tt.want)
+			}
+			info, err := ParseURL(got)
+			if err != nil || info.MessageTS != tt.ts {
+				t.Errorf("ParseURL(Permalink()) = %+v, %v, want message ts %q", info, err, tt.ts)
+			}
+		})
+	}
+}
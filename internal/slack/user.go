@@ -0,0 +1,200 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/longkey1/slago/internal/model"
+	"github.com/slack-go/slack"
+)
+
+type userEntry struct {
+	user *model.User
+	err  error
+}
+
+// GetUser gets a user profile, caching the result for the lifetime of the
+// client. Unknown users are cached as well.
+func (c *Client) GetUser(userID string) (*model.User, error) {
+	c.mu.Lock()
+	entry, ok := c.users[userID]
+	c.mu.Unlock()
+	if ok {
+		return entry.user, entry.err
+	}
+
+	if c.directory != nil {
+		if u, ok := c.directory.User(userID); ok {
+			c.mu.Lock()
+			c.users[userID] = userEntry{user: u}
+			c.mu.Unlock()
+			return u, nil
+		}
+	}
+
+	var user *slack.User
+	err := c.call(Tier4, func() error {
+		var err error
+		user, err = c.api.GetUserInfo(userID)
+		return err
+	})
+
+	entry = userEntry{}
+	if err != nil {
+		entry.err = fmt.Errorf("users.info API error: %w", err)
+	} else {
+		u := convertUser(*user)
+		entry.user = &u
+		if c.directory != nil {
+			c.directory.PutUser(u)
+		}
+	}
+
+	// Other failures (rate limits, network errors) are retried on the
+	// next lookup
+	if err == nil || isSlackError(err, "user_not_found") {
+		c.mu.Lock()
+		c.users[userID] = entry
+		c.mu.Unlock()
+	}
+
+	return entry.user, entry.err
+}
+
+// GetUsers lists every user in the workspace and fills the user cache
+func (c *Client) GetUsers() ([]model.User, error) {
+	var users []model.User
+
+	p := c.api.GetUsersPaginated()
+	for {
+		err := c.call(Tier2, func() error {
+			var err error
+			p, err = p.Next(context.Background())
+			return err
+		})
+		if p.Done(err) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("users.list API error: %w", err)
+		}
+		for _, u := range p.Users {
+			users = append(users, convertUser(u))
+		}
+	}
+
+	c.mu.Lock()
+	for i := range users {
+		c.users[users[i].ID] = userEntry{user: &users[i]}
+	}
+	c.mu.Unlock()
+
+	if c.directory != nil {
+		for _, u := range users {
+			c.directory.PutUser(u)
+		}
+	}
+
+	return users, nil
+}
+
+func convertUser(u slack.User) model.User {
+	return model.User{
+		ID:          u.ID,
+		Name:        u.Name,
+		DisplayName: u.Profile.DisplayName,
+		RealName:    u.Profile.RealName,
+	}
+}
+
+// ResolveUser resolves a user reference to a user profile. The reference may
+// be a user ID, an @handle, an email address or "me" for the token's own user.
+func (c *Client) ResolveUser(ref string) (*model.User, error) {
+	ref = strings.TrimSpace(ref)
+
+	switch {
+	case ref == "":
+		return nil, fmt.Errorf("empty user reference")
+
+	case strings.EqualFold(ref, "me"):
+		auth, err := c.AuthTest()
+		if err != nil {
+			return nil, err
+		}
+		if auth.UserID == "" {
+			return nil, fmt.Errorf("token has no user identity, \"me\" cannot be resolved")
+		}
+		return c.resolveUserID(auth.UserID)
+
+	case userIDPattern.MatchString(ref):
+		return c.resolveUserID(ref)
+
+	case !strings.HasPrefix(ref, "@") && strings.Contains(ref, "@"):
+		var user *slack.User
+		err := c.call(Tier3, func() error {
+			var err error
+			user, err = c.api.GetUserByEmail(ref)
+			return err
+		})
+		if isSlackError(err, "users_not_found") {
+			return nil, fmt.Errorf("no user with email %s", ref)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("users.lookupByEmail API error: %w", err)
+		}
+		u := convertUser(*user)
+		return &u, nil
+
+	default:
+		return c.resolveUserHandle(strings.TrimPrefix(ref, "@"))
+	}
+}
+
+var userIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]{6,}$`)
+
+// resolveUserID checks that the user exists. Lookup failures other than an
+// unknown user (e.g. a missing users:read scope) fall back to the bare ID.
+func (c *Client) resolveUserID(userID string) (*model.User, error) {
+	user, err := c.GetUser(userID)
+	if isSlackError(err, "user_not_found") {
+		return nil, fmt.Errorf("unknown user %s", userID)
+	}
+	if err != nil {
+		return &model.User{ID: userID}, nil
+	}
+	return user, nil
+}
+
+func (c *Client) resolveUserHandle(handle string) (*model.User, error) {
+	users, err := c.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	var byDisplayName []model.User
+	for _, u := range users {
+		if strings.EqualFold(u.Name, handle) {
+			return &u, nil
+		}
+		if strings.EqualFold(u.DisplayName, handle) {
+			byDisplayName = append(byDisplayName, u)
+		}
+	}
+
+	switch len(byDisplayName) {
+	case 0:
+		return nil, fmt.Errorf("unknown user @%s", handle)
+	case 1:
+		return &byDisplayName[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous user @%s matches %d display names, use a user ID or email", handle, len(byDisplayName))
+	}
+}
+
+func isSlackError(err error, code string) bool {
+	var slackErr slack.SlackErrorResponse
+	return errors.As(err, &slackErr) && slackErr.Err == code
+}
@@ -0,0 +1,88 @@
+package slack_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestResolveUser(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	alice := slackapi.User{ID: "U1111111", Name: "alice"}
+	alice.Profile.DisplayName = "Alice"
+	alice.Profile.Email = "alice@example.com"
+	srv.AddUser(alice)
+	srv.AddUser(slackapi.User{ID: "U2222222", Name: "bob"})
+	srv.SetAuth(slackapi.AuthTestResponse{UserID: "U2222222", User: "bob"})
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantID  string
+		wantErr bool
+	}{
+		{name: "user ID", ref: "U1111111", wantID: "U1111111"},
+		{name: "handle", ref: "@alice", wantID: "U1111111"},
+		{name: "display name", ref: "@Alice", wantID: "U1111111"},
+		{name: "email", ref: "alice@example.com", wantID: "U1111111"},
+		{name: "me", ref: "me", wantID: "U2222222"},
+		{name: "unknown user ID", ref: "U9999999", wantErr: true},
+		{name: "unknown handle", ref: "@nobody", wantErr: true},
+		{name: "unknown email", ref: "nobody@example.com", wantErr: true},
+	}
+
+	client := srv.Client()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.ResolveUser(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveUser(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("ResolveUser(%q) = %q, want %q", tt.ref, got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestGetUserRetriesFailedLookups(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	failures := 1
+	srv.Handle("users.info", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.FormValue("user") != "U1111111":
+			slacktest.WriteError(w, "user_not_found")
+		case failures > 0:
+			failures--
+			slacktest.WriteError(w, "fatal_error")
+		default:
+			slacktest.WriteJSON(w, map[string]interface{}{"ok": true, "user": slackapi.User{ID: "U1111111", Name: "alice"}})
+		}
+	})
+
+	client := srv.Client()
+	if _, err := client.GetUser("U1111111"); err == nil {
+		t.Fatal("GetUser() error = nil, want the first lookup to fail")
+	}
+	if user, err := client.GetUser("U1111111"); err != nil || user.Name != "alice" {
+		t.Errorf("GetUser() = %+v, %v, want alice after a failed lookup", user, err)
+	}
+
+	for range 2 {
+		if _, err := client.GetUser("U9999999"); err == nil {
+			t.Error("GetUser() error = nil, want user_not_found")
+		}
+	}
+	if calls := srv.Calls("users.info"); calls != 3 {
+		t.Errorf("users.info calls = %d, want 3", calls)
+	}
+}
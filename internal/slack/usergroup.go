@@ -0,0 +1,148 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// UserGroup represents a Slack user group (subteam)
+type UserGroup struct {
+	ID      string
+	Handle  string
+	Name    string
+	Members []string
+}
+
+// MentionFilter is a --mention value resolved to search terms.
+// A message matches the filter when it matches any of its terms, or
+// mentions any of its user or subteam IDs when filtering locally.
+type MentionFilter struct {
+	Ref   string
+	Terms []string
+	IDs   []string
+}
+
+// GetUserGroups lists the workspace's user groups with their members, caching the result
+func (c *Client) GetUserGroups() ([]UserGroup, error) {
+	c.mu.Lock()
+	cached := c.userGroups
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var groups []slack.UserGroup
+	err := c.call(Tier2, func() error {
+		var err error
+		groups, err = c.api.GetUserGroups(slack.GetUserGroupsOptionIncludeUsers(true))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("usergroups.list API error: %w", err)
+	}
+
+	result := make([]UserGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, UserGroup{
+			ID:      g.ID,
+			Handle:  g.Handle,
+			Name:    g.Name,
+			Members: g.Users,
+		})
+	}
+
+	c.mu.Lock()
+	c.userGroups = result
+	c.mu.Unlock()
+
+	return result, nil
+}
+
+// ResolveMentions turns --mention values into search filters. User group
+// handles are searched by subteam ID and, with includeMembers, mentions of
+// any group member also match. Other values are resolved to a user ID.
+func (c *Client) ResolveMentions(refs []string, includeMembers bool) []MentionFilter {
+	var groups []UserGroup
+	var groupsErr error
+	loaded := false
+
+	filters := make([]MentionFilter, 0, len(refs))
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+
+		handle := strings.TrimPrefix(ref, "@")
+		if !userIDPattern.MatchString(ref) {
+			if !loaded {
+				groups, groupsErr = c.GetUserGroups()
+				if groupsErr != nil {
+					fmt.Printf("[WARN] Failed to get user groups, group mentions are matched by name: %v\n", groupsErr)
+				}
+				loaded = true
+			}
+			if group := findUserGroup(groups, handle); group != nil {
+				filters = append(filters, userGroupFilter(ref, group, includeMembers))
+				continue
+			}
+		}
+
+		filters = append(filters, c.userFilter(ref))
+	}
+	return filters
+}
+
+// userFilter resolves a user ID, @handle, email or "me" to a filter on the
+// user's ID. Users that cannot be resolved are searched by name.
+func (c *Client) userFilter(ref string) MentionFilter {
+	if userIDPattern.MatchString(ref) {
+		return MentionFilter{Ref: ref, Terms: []string{userIDTerm(ref)}, IDs: []string{ref}}
+	}
+	user, err := c.ResolveUser(ref)
+	if err != nil {
+		fmt.Printf("[WARN] Failed to resolve mention %q, matching it by name: %v\n", ref, err)
+		return MentionFilter{Ref: ref, Terms: []string{userMentionTerm(ref)}}
+	}
+	return MentionFilter{Ref: ref, Terms: []string{userIDTerm(user.ID)}, IDs: []string{user.ID}}
+}
+
+func findUserGroup(groups []UserGroup, handle string) *UserGroup {
+	for i := range groups {
+		if strings.EqualFold(groups[i].Handle, handle) || groups[i].ID == handle {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func userGroupFilter(ref string, group *UserGroup, includeMembers bool) MentionFilter {
+	filter := MentionFilter{
+		Ref:   ref,
+		Terms: []string{fmt.Sprintf("<!subteam^%s>", group.ID)},
+		IDs:   []string{group.ID},
+	}
+	if includeMembers {
+		for _, member := range group.Members {
+			filter.Terms = append(filter.Terms, userIDTerm(member))
+			filter.IDs = append(filter.IDs, member)
+		}
+	}
+	return filter
+}
+
+// userIDTerm matches messages mentioning a user
+func userIDTerm(userID string) string {
+	return fmt.Sprintf("<@%s>", userID)
+}
+
+// userMentionTerm matches messages to a user that could not be resolved
+func userMentionTerm(mention string) string {
+	// Handle both user IDs and user names
+	if strings.HasPrefix(mention, "@") || strings.HasPrefix(mention, "U") {
+		return fmt.Sprintf("to:%s", mention)
+	}
+	return fmt.Sprintf("@%s", mention)
+}
@@ -0,0 +1,104 @@
+package slack_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	slago "github.com/longkey1/slago/internal/slack"
+	"github.com/longkey1/slago/internal/slack/slacktest"
+	slackapi "github.com/slack-go/slack"
+)
+
+func TestResolveMentions(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	srv.AddUserGroup(slackapi.UserGroup{ID: "S1", Handle: "team-backend", Users: []string{"U1", "U2"}})
+	srv.AddUser(slackapi.User{ID: "U0JANE001", Name: "jane", Profile: slackapi.UserProfile{Email: "jane@example.com"}})
+
+	tests := []struct {
+		name           string
+		refs           []string
+		includeMembers bool
+		want           [][]string
+		wantIDs        [][]string
+	}{
+		{
+			name:    "user ID",
+			refs:    []string{"U01234567"},
+			want:    [][]string{{"<@U01234567>"}},
+			wantIDs: [][]string{{"U01234567"}},
+		},
+		{
+			name:    "user handle and email",
+			refs:    []string{"@jane", "jane@example.com"},
+			want:    [][]string{{"<@U0JANE001>"}, {"<@U0JANE001>"}},
+			wantIDs: [][]string{{"U0JANE001"}, {"U0JANE001"}},
+		},
+		{
+			name:    "group handle",
+			refs:    []string{"@team-backend"},
+			want:    [][]string{{"<!subteam^S1>"}},
+			wantIDs: [][]string{{"S1"}},
+		},
+		{
+			name:           "group handle with members",
+			refs:           []string{"@team-backend"},
+			includeMembers: true,
+			want:           [][]string{{"<!subteam^S1>", "<@U1>", "<@U2>"}},
+			wantIDs:        [][]string{{"S1", "U1", "U2"}},
+		},
+		{
+			name:    "unknown handle",
+			refs:    []string{"@john.doe"},
+			want:    [][]string{{"to:@john.doe"}},
+			wantIDs: [][]string{nil},
+		},
+	}
+
+	client := srv.Client()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := client.ResolveMentions(tt.refs, tt.includeMembers)
+			var got, gotIDs [][]string
+			for _, f := range filters {
+				got = append(got, f.Terms)
+				gotIDs = append(gotIDs, f.IDs)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveMentions(%v) = %v, want %v", tt.refs, got, tt.want)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("ResolveMentions(%v) IDs = %v, want %v", tt.refs, gotIDs, tt.wantIDs)
+			}
+		})
+	}
+
+	if calls := srv.Calls("usergroups.list"); calls != 1 {
+		t.Errorf("usergroups.list calls = %d, want 1", calls)
+	}
+}
+
+func TestSearchMessagesMentionAlternatives(t *testing.T) {
+	srv := slacktest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	_, err := client.SearchMessages(slago.SearchOptions{
+		Mentions: []slago.MentionFilter{
+			{Ref: "@team-backend", Terms: []string{"<!subteam^S1>", "to:U1"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+
+	queries := srv.Queries()
+	if len(queries) != 2 {
+		t.Fatalf("queries = %v, want 2 queries", queries)
+	}
+	if !strings.Contains(queries[0], "<!subteam^S1>") || !strings.Contains(queries[1], "to:U1") {
+		t.Errorf("queries = %v, want one per mention term", queries)
+	}
+}
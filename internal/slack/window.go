@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"strings"
+	"time"
+)
+
+// maxSearchPages is how deep search.messages pages into results
+const maxSearchPages = 100
+
+const (
+	searchDateFormat = "2006-01-02"
+	searchHourFormat = "2006-01-02T15:04"
+)
+
+// searchWindow is the time range [start, end) a search covers. Whole days
+// are searched with after:/before: dates, which exclude the dates
+// themselves. Split windows are searched at hour precision, in the zone of
+// the dates; such terms are not documented by Slack. Matches of split and
+// exact windows are filtered by timestamp. A zero bound leaves that side
+// open.
+type searchWindow struct {
+	after, before time.Time
+	start, end    time.Time
+	hourly        bool
+	exact         bool
+}
+
+// windowOf returns the window of the options' after:/before: dates, narrowed
+// to From and To when set
+func windowOf(opts SearchOptions) searchWindow {
+	w := searchWindow{
+		after:  opts.After,
+		before: opts.Before,
+		start:  opts.From,
+		end:    opts.To,
+		exact:  !opts.From.IsZero() || !opts.To.IsZero(),
+	}
+	if w.start.IsZero() && !opts.After.IsZero() {
+		w.start = opts.After.AddDate(0, 0, 1)
+	}
+	if w.end.IsZero() {
+		w.end = opts.Before
+	}
+	return w
+}
+
+// query adds the window's bounds to a query
+func (w searchWindow) query(base string) string {
+	terms := []string{}
+	if base != "" {
+		terms = append(terms, base)
+	}
+	switch {
+	case w.hourly:
+		// In the zone of the day bounds they split. Widened by a minute, the
+		// timestamp filter keeps the window exact.
+		terms = append(terms,
+			"after:"+w.start.Add(-time.Minute).Format(searchHourFormat),
+			"before:"+w.end.Add(time.Minute).Format(searchHourFormat))
+	default:
+		if !w.after.IsZero() {
+			terms = append(terms, "after:"+w.after.Format(searchDateFormat))
+		}
+		if !w.before.IsZero() {
+			terms = append(terms, "before:"+w.before.Format(searchDateFormat))
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// split halves a closed window on an hour boundary. Windows of an hour or
+// less are not split.
+func (w searchWindow) split() ([2]searchWindow, bool) {
+	if w.start.IsZero() || w.end.IsZero() || w.end.Sub(w.start) <= time.Hour {
+		return [2]searchWindow{}, false
+	}
+	mid := w.start.Add(w.end.Sub(w.start) / 2).Truncate(time.Hour)
+	if !mid.After(w.start) {
+		mid = w.start.Add(time.Hour)
+	}
+	return [2]searchWindow{
+		{start: w.start, end: mid, hourly: true, exact: w.exact},
+		{start: mid, end: w.end, hourly: true, exact: w.exact},
+	}, true
+}
+
+// contains reports whether a match of the window's query belongs to it.
+// Windows of whole days trust search's own day boundaries.
+func (w searchWindow) contains(t time.Time) bool {
+	if !w.hourly && !w.exact {
+		return true
+	}
+	return (w.start.IsZero() || !t.Before(w.start)) && (w.end.IsZero() || t.Before(w.end))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	_ "time/tzdata" // zone database for --tz on systems without one
 
 	"github.com/longkey1/slago/cmd"
 	"github.com/longkey1/slago/internal/version"